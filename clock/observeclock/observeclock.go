@@ -6,6 +6,7 @@
 package observeclock
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -18,6 +19,51 @@ var _ interface {
 	clock.TickerScheduler
 } = (*Clock)(nil)
 
+// Kind is the kind of an observed event.
+type Kind uint8
+
+const (
+	// KindSchedule is the kind of events created by the Schedule method.
+	KindSchedule Kind = iota + 1
+	// KindTimer is the kind of events created by the Timer method.
+	KindTimer
+	// KindTicker is the kind of events created by the Ticker method.
+	KindTicker
+)
+
+// String implements the fmt.Stringer interface.
+func (k Kind) String() string {
+	switch k {
+	case KindSchedule:
+		return "Schedule"
+	case KindTimer:
+		return "Timer"
+	case KindTicker:
+		return "Ticker"
+	default:
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Observation is a record of an observed Schedule, Timer or Ticker method
+// call.
+type Observation struct {
+	// Kind is the kind of the observed event.
+	Kind Kind
+	// Duration is the duration argument passed to the method.
+	Duration time.Duration
+
+	// Event is the value returned from the Schedule method. It is set iff
+	// Kind is KindSchedule.
+	Event clock.Event
+	// Timer is the value returned from the Timer method. It is set iff
+	// Kind is KindTimer.
+	Timer clock.Timer
+	// Ticker is the value returned from the Ticker method. It is set iff
+	// Kind is KindTicker.
+	Ticker clock.Ticker
+}
+
 // Clock allows observing creation of new events on the underlying clock.Clock
 // instance.
 type Clock struct {
@@ -25,6 +71,13 @@ type Clock struct {
 
 	mu sync.Mutex
 	xs []chan struct{}
+	ws []watcher
+}
+
+// watcher is a pending observation that matches the predicate.
+type watcher struct {
+	match func(Observation) bool
+	c     chan Observation
 }
 
 // NewClock returns a new Clock that observes the given clock.Clock.
@@ -43,21 +96,21 @@ func New(s clock.Scheduler) *Clock {
 // Schedule implements the clock.Scheduler interface.
 func (c *Clock) Schedule(d time.Duration, f func(time.Time)) clock.Event {
 	t := c.Clock.Schedule(d, f)
-	c.event()
+	c.event(Observation{Kind: KindSchedule, Duration: d, Event: t})
 	return t
 }
 
 // Timer implements the clock.TimerScheduler interface.
 func (c *Clock) Timer(d time.Duration) clock.Timer {
 	t := c.Clock.Timer(d)
-	c.event()
+	c.event(Observation{Kind: KindTimer, Duration: d, Timer: t})
 	return t
 }
 
 // Ticker implements the clock.TickerScheduler interface.
 func (c *Clock) Ticker(d time.Duration) clock.Ticker {
 	t := c.Clock.Ticker(d)
-	c.event()
+	c.event(Observation{Kind: KindTicker, Duration: d, Ticker: t})
 	return t
 }
 
@@ -71,8 +124,38 @@ func (c *Clock) Observe() <-chan struct{} {
 	return x
 }
 
+// ObserveNext returns a channel that receives the Observation of the next
+// Schedule, Timer or Ticker call. The channel is closed after the value is
+// sent.
+func (c *Clock) ObserveNext() <-chan Observation {
+	return c.ObserveFunc(nil)
+}
+
+// ObserveFunc returns a channel that receives the Observation of the first
+// Schedule, Timer or Ticker call for which match returns true. The channel is
+// closed after the value is sent. A nil match function matches all calls.
+//
+// The match function is called with the internal lock held and must not call
+// Clock methods.
+func (c *Clock) ObserveFunc(match func(Observation) bool) <-chan Observation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	x := make(chan Observation, 1)
+	c.ws = append(c.ws, watcher{match, x})
+	return x
+}
+
+// ObserveKind returns a channel that receives the Observation of the first
+// call of the given kind with the duration d.
+func (c *Clock) ObserveKind(k Kind, d time.Duration) <-chan Observation {
+	return c.ObserveFunc(func(o Observation) bool {
+		return o.Kind == k && o.Duration == d
+	})
+}
+
 // event triggers an observable event.
-func (c *Clock) event() {
+func (c *Clock) event(o Observation) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -81,4 +164,16 @@ func (c *Clock) event() {
 		close(x)
 	}
 	c.xs = c.xs[:0]
+
+	ws := c.ws[:0]
+	for _, w := range c.ws {
+		if w.match != nil && !w.match(o) {
+			ws = append(ws, w)
+			continue
+		}
+		w.c <- o
+		close(w.c)
+	}
+	clear(c.ws[len(ws):])
+	c.ws = ws
 }
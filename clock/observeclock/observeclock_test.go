@@ -41,3 +41,60 @@ func isClosed(ch <-chan struct{}) bool {
 	}
 	return false
 }
+
+func TestObserveFunc(t *testing.T) {
+	c := New(clock.NewClock(fakeclock.Go()))
+
+	next := c.ObserveNext()
+	timer := c.ObserveKind(KindTimer, 5*time.Second)
+	ticker := c.ObserveKind(KindTicker, time.Second)
+
+	event := c.Schedule(time.Second, func(time.Time) {})
+	defer event.Stop()
+
+	o, ok := receive(next)
+	if !ok {
+		t.Fatal("no observation on event")
+	}
+	if o.Kind != KindSchedule || o.Duration != time.Second || o.Event != event {
+		t.Fatalf("unexpected observation: %+v", o)
+	}
+	if _, ok := receive(timer); ok {
+		t.Fatal("unexpected observation for non-matching event")
+	}
+
+	t1 := c.Timer(time.Second)
+	defer t1.Stop()
+	if _, ok := receive(timer); ok {
+		t.Fatal("unexpected observation for non-matching duration")
+	}
+
+	t5 := c.Timer(5 * time.Second)
+	defer t5.Stop()
+	o, ok = receive(timer)
+	if !ok {
+		t.Fatal("no observation on matching event")
+	}
+	if o.Kind != KindTimer || o.Duration != 5*time.Second || o.Timer != t5 {
+		t.Fatalf("unexpected observation: %+v", o)
+	}
+
+	tk := c.Ticker(time.Second)
+	defer tk.Stop()
+	o, ok = receive(ticker)
+	if !ok {
+		t.Fatal("no observation on matching event")
+	}
+	if o.Kind != KindTicker || o.Ticker != tk {
+		t.Fatalf("unexpected observation: %+v", o)
+	}
+}
+
+func receive(ch <-chan Observation) (Observation, bool) {
+	select {
+	case o := <-ch:
+		return o, true
+	default:
+	}
+	return Observation{}, false
+}
@@ -0,0 +1,238 @@
+// Package execprocess provides [process.Runner] wrapper for [exec.Cmd].
+package execprocess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"sync"
+	"time"
+
+	"go.pact.im/x/logs"
+	"go.pact.im/x/process"
+)
+
+// ErrExited is an error that is returned if the command exits before it is
+// ready.
+var ErrExited = errors.New("execprocess: command exited before ready")
+
+// defaultStopTimeout is the default timeout between SIGTERM and SIGKILL
+// signals on shutdown.
+const defaultStopTimeout = 10 * time.Second
+
+// CommandRunner is a [process.Runner] that runs a child OS process.
+//
+// On Unix systems, the command runs in its own process group. On shutdown,
+// CommandRunner sends SIGTERM to the process group and escalates to SIGKILL
+// after the stop timeout or when the shutdown context expires. On other
+// systems, the process is killed immediately.
+type CommandRunner struct {
+	cmd         func() *exec.Cmd
+	ready       Readiness
+	logger      *logs.Logger
+	stopTimeout time.Duration
+}
+
+// Command returns a new [CommandRunner] that runs commands returned by the given
+// function. The function is called on each Run and must return a new command
+// that has not been started. Since CommandRunner manages process termination,
+// the command should be created with [exec.Command] instead of
+// [exec.CommandContext].
+//
+// By default, the command is considered ready once it is started, its output
+// is discarded and the stop timeout is 10 seconds.
+func Command(cmd func() *exec.Cmd) *CommandRunner {
+	return &CommandRunner{
+		cmd:         cmd,
+		stopTimeout: defaultStopTimeout,
+	}
+}
+
+// WithReadiness returns a copy of the runner that uses the given readiness
+// predicate.
+func (r *CommandRunner) WithReadiness(ready Readiness) *CommandRunner {
+	rc := *r
+	rc.ready = ready
+	return &rc
+}
+
+// WithLogger returns a copy of the runner that forwards command’s output to
+// the given logger. Lines from stdout are logged at [slog.LevelInfo] and lines
+// from stderr at [slog.LevelWarn] with a “stream” attribute.
+func (r *CommandRunner) WithLogger(logger *logs.Logger) *CommandRunner {
+	rc := *r
+	rc.logger = logger
+	return &rc
+}
+
+// WithStopTimeout returns a copy of the runner that uses the given timeout
+// between SIGTERM and SIGKILL signals on shutdown.
+func (r *CommandRunner) WithStopTimeout(d time.Duration) *CommandRunner {
+	rc := *r
+	rc.stopTimeout = d
+	return &rc
+}
+
+// Run implements the [process.Runner] interface. It returns callback error if
+// it is not nil, otherwise it returns an error from waiting for the command.
+// Exit errors caused by termination on shutdown are ignored.
+func (r *CommandRunner) Run(ctx context.Context, callback process.Callback) error {
+	cmd := r.cmd()
+	setProcessGroup(cmd)
+
+	lines := make(chan string)
+	readyDone := make(chan struct{})
+
+	stdout := &lineWriter{emit: func(line string) {
+		r.log(ctx, slog.LevelInfo, "stdout", line)
+		select {
+		case lines <- line:
+		case <-readyDone:
+		}
+	}}
+	stderr := &lineWriter{emit: func(line string) {
+		r.log(ctx, slog.LevelWarn, "stderr", line)
+	}}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	var waitError error
+	go func() {
+		waitError = cmd.Wait()
+		stdout.Flush()
+		stderr.Flush()
+		close(exited)
+	}()
+
+	readyError := r.waitReady(ctx, lines, exited)
+	close(readyDone)
+	if readyError != nil {
+		r.stop(ctx, cmd, exited)
+		return readyError
+	}
+
+	bgctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-exited:
+			cancel() // cancel callback
+		case <-bgctx.Done():
+		}
+	}()
+
+	callbackError := callback(bgctx)
+
+	terminated := r.stop(ctx, cmd, exited)
+
+	switch {
+	case callbackError != nil:
+		return callbackError
+	case terminated && isTerminated(waitError):
+		return nil
+	}
+	return waitError
+}
+
+// waitReady waits for the command to become ready.
+func (r *CommandRunner) waitReady(ctx context.Context, lines <-chan string, exited <-chan struct{}) error {
+	if r.ready == nil {
+		return nil
+	}
+
+	readyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- r.ready(readyCtx, lines)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-exited:
+		cancel()
+		<-result
+		return ErrExited
+	}
+}
+
+// stop gracefully stops the command if it is still running and waits for it to
+// exit. It returns true if the command was terminated.
+func (r *CommandRunner) stop(ctx context.Context, cmd *exec.Cmd, exited <-chan struct{}) bool {
+	select {
+	case <-exited:
+		return false
+	default:
+	}
+
+	terminate(cmd)
+
+	timer := time.NewTimer(r.stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-exited:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	kill(cmd)
+	<-exited
+	return true
+}
+
+// log logs a line from the command’s output.
+func (r *CommandRunner) log(ctx context.Context, level slog.Level, stream, line string) {
+	if r.logger == nil {
+		return
+	}
+	r.logger.Log(ctx, level, line, slog.String("stream", stream))
+}
+
+// lineWriter is an [io.Writer] that splits written data into lines.
+type lineWriter struct {
+	emit func(line string)
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write implements the [io.Writer] interface.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.buf[:i], []byte{'\r'})
+		w.emit(string(line))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits the remaining data that does not end with a newline.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return
+	}
+	w.emit(string(w.buf))
+	w.buf = nil
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package execprocess
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func setProcessGroup(*exec.Cmd) {}
+
+// terminate kills the command since graceful termination is not supported.
+func terminate(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
+
+// kill kills the command.
+func kill(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
+
+// isTerminated reports whether the error is an exit error.
+func isTerminated(err error) bool {
+	var exitError *exec.ExitError
+	return errors.As(err, &exitError)
+}
@@ -0,0 +1,88 @@
+//go:build unix
+
+package execprocess
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.pact.im/x/logs"
+	"go.pact.im/x/process"
+)
+
+func shell(script string) func() *exec.Cmd {
+	return func() *exec.Cmd {
+		return exec.Command("sh", "-c", script)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var lines []string
+	logger := logs.New(logs.HandlerFunc(func(_ context.Context, r slog.Record) error {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, r.Message)
+		return nil
+	}))
+
+	runner := Command(shell("echo starting; echo oops >&2; echo ready; exec sleep 60")).
+		WithReadiness(LineMatch(regexp.MustCompile("^ready$"))).
+		WithLogger(logger)
+
+	p := process.NewProcess(ctx, runner)
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("start command: %v", err)
+	}
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("stop command: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, line := range []string{"starting", "ready", "oops"} {
+		if !slices.Contains(lines, line) {
+			t.Fatalf("expected %q to be logged, got %q", line, lines)
+		}
+	}
+}
+
+func TestCommandKill(t *testing.T) {
+	ctx := context.Background()
+
+	runner := Command(shell("trap '' TERM; echo ready; while :; do sleep 1; done")).
+		WithReadiness(LineMatch(regexp.MustCompile("ready"))).
+		WithStopTimeout(100 * time.Millisecond)
+
+	p := process.NewProcess(ctx, runner)
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("start command: %v", err)
+	}
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("stop command: %v", err)
+	}
+}
+
+func TestCommandExited(t *testing.T) {
+	ctx := context.Background()
+
+	runner := Command(shell("exit 3")).
+		WithReadiness(Delay(time.Minute))
+
+	err := runner.Run(ctx, func(_ context.Context) error {
+		t.Fatal("unexpected callback")
+		return nil
+	})
+	if !errors.Is(err, ErrExited) {
+		t.Fatalf("expected %v, got %v", ErrExited, err)
+	}
+}
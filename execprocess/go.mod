@@ -0,0 +1,10 @@
+module go.pact.im/x/execprocess
+
+go 1.26.0
+
+require (
+	go.pact.im/x/logs v0.0.21
+	go.pact.im/x/process v0.0.21
+)
+
+require go.pact.im/x/task v0.0.21 // indirect
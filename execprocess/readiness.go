@@ -0,0 +1,79 @@
+package execprocess
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"time"
+)
+
+// Readiness is a predicate that blocks until the command is ready for use or
+// the context expires. It receives lines from command’s stdout and must keep
+// receiving from lines channel until it returns.
+type Readiness func(ctx context.Context, lines <-chan string) error
+
+// LineMatch returns a [Readiness] predicate that is satisfied once the command
+// writes a line that matches the given regular expression to stdout.
+func LineMatch(re *regexp.Regexp) Readiness {
+	return func(ctx context.Context, lines <-chan string) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case line := <-lines:
+				if re.MatchString(line) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// TCPPort returns a [Readiness] predicate that is satisfied once a TCP
+// connection to the given address succeeds. It attempts to connect at the given
+// interval.
+func TCPPort(addr string, interval time.Duration) Readiness {
+	return func(ctx context.Context, lines <-chan string) error {
+		var dialer net.Dialer
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				_ = conn.Close()
+				return nil
+			}
+		wait:
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-lines:
+				case <-ticker.C:
+					break wait
+				}
+			}
+		}
+	}
+}
+
+// Delay returns a [Readiness] predicate that is satisfied after the given
+// duration.
+func Delay(d time.Duration) Readiness {
+	return func(ctx context.Context, lines <-chan string) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-lines:
+			case <-timer.C:
+				return nil
+			}
+		}
+	}
+}
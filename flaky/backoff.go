@@ -3,6 +3,8 @@ package flaky
 import (
 	"math"
 	"time"
+
+	"go.pact.im/x/clock"
 )
 
 // BackoffProvider provides a potentially stateful backoff function for
@@ -11,6 +13,15 @@ type BackoffProvider interface {
 	Backoff() Backoff
 }
 
+// BackoffProviderFunc is a function that implements the BackoffProvider
+// interface.
+type BackoffProviderFunc func() Backoff
+
+// Backoff implements the BackoffProvider interface.
+func (f BackoffProviderFunc) Backoff() Backoff {
+	return f()
+}
+
 // Backoff is a function that, given the current count of retries, returns
 // backoff delay before the next attempt, or false if an executor should stop
 // with the last error.
@@ -66,3 +77,86 @@ func Exp2(unit time.Duration) Backoff {
 		return x, true
 	}
 }
+
+// Linear returns a linear backoff that returns the count of failed attempts
+// times the given duration unit.
+//
+// Note that the backoff stops after wait duration reaches max positive value of
+// time.Duration.
+func Linear(unit time.Duration) Backoff {
+	return func(n uint) (time.Duration, bool) {
+		if uint64(n) >= math.MaxInt64 || unit <= 0 {
+			return 0, false
+		}
+		k := time.Duration(n) + 1
+		if unit > maxDuration/k {
+			return 0, false
+		}
+		return unit * k, true
+	}
+}
+
+// Fibonacci returns a backoff that returns the Fibonacci number for the count
+// of failed attempts times the given duration unit. That is, it returns 1, 2,
+// 3, 5, 8, … units.
+//
+// Note that the backoff stops after wait duration reaches max positive value of
+// time.Duration.
+func Fibonacci(unit time.Duration) Backoff {
+	return func(n uint) (time.Duration, bool) {
+		if unit <= 0 {
+			return 0, false
+		}
+		a, b := unit, unit
+		for range n {
+			if a > maxDuration-b {
+				return 0, false
+			}
+			a, b = b, a+b
+		}
+		return b, true
+	}
+}
+
+// Cap limits the backoff delays provided by p to the duration d.
+func Cap(d time.Duration, p BackoffProvider) BackoffProvider {
+	return BackoffProviderFunc(func() Backoff {
+		backoff := p.Backoff()
+		return func(n uint) (time.Duration, bool) {
+			x, ok := backoff(n)
+			if !ok {
+				return x, ok
+			}
+			return min(x, d), true
+		}
+	})
+}
+
+// MaxElapsed stops the backoff provided by p once the time elapsed since the
+// backoff function was requested, including the next delay, exceeds the given
+// duration. Since executors request a new backoff function for each Execute
+// invocation, it limits the total time spent executing an operation.
+//
+// It uses the system clock if the given clock is nil.
+func MaxElapsed(c *clock.Clock, d time.Duration, p BackoffProvider) BackoffProvider {
+	if c == nil {
+		c = clock.System()
+	}
+	return BackoffProviderFunc(func() Backoff {
+		start := c.Now()
+		backoff := p.Backoff()
+		return func(n uint) (time.Duration, bool) {
+			x, ok := backoff(n)
+			if !ok {
+				return x, ok
+			}
+			if c.Now().Add(x).Sub(start) > d {
+				return 0, false
+			}
+			return x, true
+		}
+	})
+}
+
+// maxDuration is the maximum value of time.Duration.
+const maxDuration = time.Duration(math.MaxInt64)
@@ -0,0 +1,64 @@
+package flaky
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+)
+
+func TestLinear(t *testing.T) {
+	b := Linear(time.Second)
+	for n, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		d, ok := b(uint(n))
+		if !ok || d != expected {
+			t.Errorf("n=%d: expected (%v, true), got (%v, %v)", n, expected, d, ok)
+		}
+	}
+	if _, ok := b(math.MaxUint); ok {
+		t.Error("expected backoff to stop on overflow")
+	}
+}
+
+func TestFibonacci(t *testing.T) {
+	b := Fibonacci(time.Second)
+	expected := []time.Duration{1, 2, 3, 5, 8, 13}
+	for n, x := range expected {
+		d, ok := b(uint(n))
+		if !ok || d != x*time.Second {
+			t.Errorf("n=%d: expected (%v, true), got (%v, %v)", n, x*time.Second, d, ok)
+		}
+	}
+	if _, ok := b(100); ok {
+		t.Error("expected backoff to stop on overflow")
+	}
+}
+
+func TestCap(t *testing.T) {
+	b := Cap(5*time.Second, Linear(2*time.Second)).Backoff()
+	for n, expected := range []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		d, ok := b(uint(n))
+		if !ok || d != expected {
+			t.Errorf("n=%d: expected (%v, true), got (%v, %v)", n, expected, d, ok)
+		}
+	}
+}
+
+func TestMaxElapsed(t *testing.T) {
+	fakeClock := fakeclock.Unix()
+	b := MaxElapsed(clock.NewClock(fakeClock), 10*time.Second, Constant(4*time.Second)).Backoff()
+
+	if d, ok := b(0); !ok || d != 4*time.Second {
+		t.Fatalf("expected (4s, true), got (%v, %v)", d, ok)
+	}
+	fakeClock.Add(4 * time.Second)
+	if d, ok := b(1); !ok || d != 4*time.Second {
+		t.Fatalf("expected (4s, true), got (%v, %v)", d, ok)
+	}
+	fakeClock.Add(4 * time.Second)
+	if _, ok := b(2); ok {
+		t.Fatal("expected backoff to stop after max elapsed time")
+	}
+}
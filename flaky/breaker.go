@@ -0,0 +1,334 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// ErrCircuitOpen is an error that CircuitBreakerExecutor returns when an
+// operation is rejected because the circuit is open.
+var ErrCircuitOpen = errors.New("flaky: circuit breaker is open")
+
+// CircuitState is the state of the CircuitBreakerExecutor.
+type CircuitState uint8
+
+const (
+	// CircuitClosed is the state where operations are executed and failures
+	// are counted.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is the state where operations are rejected until the
+	// cool-down period expires.
+	CircuitOpen
+	// CircuitHalfOpen is the state where a limited number of trial
+	// operations are executed to check whether the failure persists.
+	CircuitHalfOpen
+)
+
+// String implements the fmt.Stringer interface.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "CircuitState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// CircuitBreakerExecutor is an executor that stops executing operations once
+// the failure threshold is reached. It allows failing fast instead of putting
+// more load on a service that is already failing.
+//
+// The circuit starts in the closed state. After a number of consecutive
+// failures, or once the failure rate among recent operations reaches the
+// configured threshold, the circuit opens and Execute returns ErrCircuitOpen
+// without calling the operation. When the cool-down period elapses, the
+// circuit becomes half-open and allows a limited number of trial operations.
+// If all of them succeed, the circuit closes. Otherwise it opens again.
+//
+// Operations that return a permanent error are not considered failures since
+// such errors do not indicate a transient failure. Likewise, an operation that
+// fails after the context expires is not counted.
+//
+// CircuitBreakerExecutor executes an operation at most once per Execute call.
+// To retry rejected operations, use it under another executor, e.g. Retry.
+type CircuitBreakerExecutor struct {
+	clock *clock.Clock
+
+	threshold uint
+	rate      float64
+	window    uint
+	cooldown  time.Duration
+	probes    uint
+	onChange  func(from, to CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	gen      uint64
+	failures uint
+	openedAt time.Time
+	inflight uint
+	passed   uint
+	results  []bool
+	next     int
+}
+
+// CircuitBreaker returns a new CircuitBreakerExecutor that opens the circuit
+// after the given number of consecutive failures and keeps it open for the
+// cool-down duration. A zero threshold disables the consecutive failures check.
+func CircuitBreaker(threshold uint, cooldown time.Duration) *CircuitBreakerExecutor {
+	return &CircuitBreakerExecutor{
+		clock:     clock.System(),
+		threshold: threshold,
+		cooldown:  cooldown,
+		probes:    1,
+	}
+}
+
+// clone returns a copy of the executor configuration with the closed circuit.
+func (b *CircuitBreakerExecutor) clone() *CircuitBreakerExecutor {
+	return &CircuitBreakerExecutor{
+		clock:     b.clock,
+		threshold: b.threshold,
+		rate:      b.rate,
+		window:    b.window,
+		cooldown:  b.cooldown,
+		probes:    b.probes,
+		onChange:  b.onChange,
+	}
+}
+
+// WithClock returns a copy of the executor that uses the given clock.
+func (b *CircuitBreakerExecutor) WithClock(c *clock.Clock) *CircuitBreakerExecutor {
+	if c == nil {
+		c = clock.System()
+	}
+	b = b.clone()
+	b.clock = c
+	return b
+}
+
+// WithFailureRate returns a copy of the executor that also opens the circuit
+// once the ratio of failures among the last window operations reaches the
+// given rate. The rate is not checked until window operations complete. A
+// non-positive rate or zero window disables the failure rate check.
+func (b *CircuitBreakerExecutor) WithFailureRate(rate float64, window uint) *CircuitBreakerExecutor {
+	b = b.clone()
+	b.rate = rate
+	b.window = window
+	return b
+}
+
+// WithHalfOpenLimit returns a copy of the executor that allows at most n trial
+// operations in the half-open state. The circuit closes once n trial operations
+// succeed. Passing zero is equivalent to one.
+func (b *CircuitBreakerExecutor) WithHalfOpenLimit(n uint) *CircuitBreakerExecutor {
+	b = b.clone()
+	b.probes = max(n, 1)
+	return b
+}
+
+// WithStateChange returns a copy of the executor that calls f on each state
+// transition. The function is called after the transition without holding
+// internal locks and may be called concurrently.
+func (b *CircuitBreakerExecutor) WithStateChange(f func(from, to CircuitState)) *CircuitBreakerExecutor {
+	b = b.clone()
+	b.onChange = f
+	return b
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreakerExecutor) State() CircuitState {
+	b.mu.Lock()
+	from := b.state
+	b.advance(b.clock.Now())
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return to
+}
+
+// Execute implements the Executor interface. It returns ErrCircuitOpen if the
+// circuit is open or the limit of trial operations in the half-open state is
+// reached.
+func (b *CircuitBreakerExecutor) Execute(ctx context.Context, f Op) error {
+	gen, from, to, err := b.acquire()
+	b.notify(from, to)
+	if err != nil {
+		return err
+	}
+
+	// Treat a panic as a failure so that the operation is always released,
+	// even if the caller recovers from the panic.
+	result := circuitFailure
+	defer func() {
+		from, to := b.release(gen, result)
+		b.notify(from, to)
+	}()
+
+	err = f(ctx)
+	result = b.classify(ctx, err)
+
+	return unwrapInternal(err)
+}
+
+// circuitResult is the outcome of an operation executed by
+// CircuitBreakerExecutor.
+type circuitResult uint8
+
+const (
+	circuitIgnore circuitResult = iota
+	circuitSuccess
+	circuitFailure
+)
+
+// classify returns the outcome of an operation for the given error.
+func (b *CircuitBreakerExecutor) classify(ctx context.Context, err error) circuitResult {
+	switch {
+	case err == nil:
+		return circuitSuccess
+	case IsPermanentError(err):
+		return circuitSuccess
+	case ctx.Err() != nil:
+		return circuitIgnore
+	default:
+		return circuitFailure
+	}
+}
+
+// acquire checks whether an operation is allowed to execute. It returns the
+// generation of the current state and the state transition, if any.
+func (b *CircuitBreakerExecutor) acquire() (uint64, CircuitState, CircuitState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from := b.state
+	b.advance(b.clock.Now())
+
+	switch b.state {
+	case CircuitOpen:
+		return 0, from, b.state, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.inflight+b.passed >= b.probes {
+			return 0, from, b.state, ErrCircuitOpen
+		}
+	}
+	b.inflight++
+	return b.gen, from, b.state, nil
+}
+
+// release records the outcome of an operation started in the given generation.
+// It returns the state transition, if any.
+func (b *CircuitBreakerExecutor) release(gen uint64, r circuitResult) (CircuitState, CircuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from := b.state
+	if gen != b.gen {
+		// The state has changed since the operation started.
+		return from, from
+	}
+	b.inflight--
+
+	switch b.state {
+	case CircuitClosed:
+		switch r {
+		case circuitSuccess:
+			b.failures = 0
+			b.record(true)
+		case circuitFailure:
+			b.failures++
+			b.record(false)
+			if b.tripped() {
+				b.open(b.clock.Now())
+			}
+		}
+	case CircuitHalfOpen:
+		switch r {
+		case circuitSuccess:
+			b.passed++
+			if b.passed >= b.probes {
+				b.transition(CircuitClosed)
+			}
+		case circuitFailure:
+			b.open(b.clock.Now())
+		}
+	}
+	return from, b.state
+}
+
+// record records the operation outcome in the failure rate window.
+func (b *CircuitBreakerExecutor) record(ok bool) {
+	if b.rate <= 0 || b.window == 0 {
+		return
+	}
+	if uint(len(b.results)) < b.window {
+		b.results = append(b.results, ok)
+		return
+	}
+	b.results[b.next] = ok
+	b.next = (b.next + 1) % len(b.results)
+}
+
+// tripped returns whether the failure thresholds are reached.
+func (b *CircuitBreakerExecutor) tripped() bool {
+	if b.threshold > 0 && b.failures >= b.threshold {
+		return true
+	}
+	if b.rate <= 0 || b.window == 0 || uint(len(b.results)) < b.window {
+		return false
+	}
+	var n int
+	for _, ok := range b.results {
+		if !ok {
+			n++
+		}
+	}
+	return float64(n)/float64(len(b.results)) >= b.rate
+}
+
+// advance moves the open circuit to the half-open state if the cool-down
+// period has elapsed by now.
+func (b *CircuitBreakerExecutor) advance(now time.Time) {
+	if b.state != CircuitOpen {
+		return
+	}
+	if now.Before(b.openedAt.Add(b.cooldown)) {
+		return
+	}
+	b.transition(CircuitHalfOpen)
+}
+
+// open moves the circuit to the open state at the given time.
+func (b *CircuitBreakerExecutor) open(now time.Time) {
+	b.openedAt = now
+	b.transition(CircuitOpen)
+}
+
+// transition moves the circuit to the given state and resets the counters.
+func (b *CircuitBreakerExecutor) transition(s CircuitState) {
+	b.state = s
+	b.gen++
+	b.failures = 0
+	b.inflight = 0
+	b.passed = 0
+	b.results = b.results[:0]
+	b.next = 0
+}
+
+// notify calls the state change hook if the state has changed.
+func (b *CircuitBreakerExecutor) notify(from, to CircuitState) {
+	if from == to || b.onChange == nil {
+		return
+	}
+	b.onChange(from, to)
+}
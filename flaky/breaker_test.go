@@ -0,0 +1,170 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = time.Minute
+
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	type transition struct {
+		from, to CircuitState
+	}
+	var transitions []transition
+
+	fakeClock := fakeclock.Unix()
+	breaker := CircuitBreaker(2, cooldown).
+		WithClock(clock.NewClock(fakeClock)).
+		WithStateChange(func(from, to CircuitState) {
+			transitions = append(transitions, transition{from, to})
+		})
+
+	fail := func(_ context.Context) error { return oops }
+	succeed := func(_ context.Context) error { return nil }
+
+	if err := breaker.Execute(ctx, fail); err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if err := breaker.Execute(ctx, succeed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := breaker.Execute(ctx, fail); err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if s := breaker.State(); s != CircuitClosed {
+		t.Fatalf("expected %v state, got %v", CircuitClosed, s)
+	}
+	if err := breaker.Execute(ctx, fail); err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if s := breaker.State(); s != CircuitOpen {
+		t.Fatalf("expected %v state, got %v", CircuitOpen, s)
+	}
+
+	err := breaker.Execute(ctx, func(_ context.Context) error {
+		panic("operation should not be executed in open state")
+	})
+	if err != ErrCircuitOpen {
+		t.Fatalf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+
+	fakeClock.Add(cooldown)
+	if s := breaker.State(); s != CircuitHalfOpen {
+		t.Fatalf("expected %v state, got %v", CircuitHalfOpen, s)
+	}
+	if err := breaker.Execute(ctx, fail); err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if s := breaker.State(); s != CircuitOpen {
+		t.Fatalf("expected %v state, got %v", CircuitOpen, s)
+	}
+
+	fakeClock.Add(cooldown)
+	if err := breaker.Execute(ctx, succeed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := breaker.State(); s != CircuitClosed {
+		t.Fatalf("expected %v state, got %v", CircuitClosed, s)
+	}
+
+	expected := []transition{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Fatalf("expected transitions %v, got %v", expected, transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerFailureRate(t *testing.T) {
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	breaker := CircuitBreaker(0, time.Minute).
+		WithClock(clock.NewClock(fakeclock.Unix())).
+		WithFailureRate(0.5, 4)
+
+	results := []error{oops, nil, oops, nil}
+	for _, r := range results[:3] {
+		_ = breaker.Execute(ctx, func(_ context.Context) error { return r })
+		if s := breaker.State(); s != CircuitClosed {
+			t.Fatalf("expected %v state, got %v", CircuitClosed, s)
+		}
+	}
+	_ = breaker.Execute(ctx, func(_ context.Context) error { return oops })
+	if s := breaker.State(); s != CircuitOpen {
+		t.Fatalf("expected %v state, got %v", CircuitOpen, s)
+	}
+}
+
+func TestCircuitBreakerPermanent(t *testing.T) {
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	breaker := CircuitBreaker(1, time.Minute).
+		WithClock(clock.NewClock(fakeclock.Unix()))
+
+	err := breaker.Execute(ctx, func(_ context.Context) error {
+		return Internal(oops)
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if s := breaker.State(); s != CircuitClosed {
+		t.Fatalf("expected %v state, got %v", CircuitClosed, s)
+	}
+}
+
+func TestCircuitBreakerPanic(t *testing.T) {
+	const cooldown = time.Minute
+
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	breaker := CircuitBreaker(1, cooldown).
+		WithClock(clock.NewClock(fakeClock))
+
+	fail := func(_ context.Context) error { return errors.New("oops") }
+	succeed := func(_ context.Context) error { return nil }
+
+	_ = breaker.Execute(ctx, fail)
+	fakeClock.Add(cooldown)
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("expected %v, got %v", CircuitHalfOpen, state)
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = breaker.Execute(ctx, func(_ context.Context) error {
+			panic("oops")
+		})
+	}()
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("expected %v, got %v", CircuitOpen, state)
+	}
+
+	fakeClock.Add(cooldown)
+	if err := breaker.Execute(ctx, succeed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("expected %v, got %v", CircuitClosed, state)
+	}
+}
@@ -0,0 +1,140 @@
+package flaky
+
+import (
+	"sync"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// RetryBudgetError is an error that RetryExecutor returns when it stops
+// retrying an operation because the retry budget is exhausted. It wraps the
+// last error encountered.
+type RetryBudgetError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *RetryBudgetError) Error() string {
+	return "flaky: retry budget exhausted: " + e.err.Error()
+}
+
+// Unwrap unwraps the underlying error.
+func (e *RetryBudgetError) Unwrap() error {
+	return e.err
+}
+
+// retryBudgetBuckets is the number of buckets in the retry budget sliding
+// window.
+const retryBudgetBuckets = 10
+
+// RetryBudget limits the number of retries relative to the number of recent
+// successful operations. It allows sharing a retry budget between multiple
+// RetryExecutor instances to avoid multiplying the load on a failing service.
+//
+// A retry is allowed if the number of retries in the sliding window, including
+// the new one, does not exceed the given ratio of successful operations in the
+// same window plus the minimum number of retries.
+//
+// RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	clock      *clock.Clock
+	ratio      float64
+	width      time.Duration
+	minRetries uint
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]retryBudgetBucket
+}
+
+// retryBudgetBucket is a bucket of the retry budget sliding window.
+type retryBudgetBucket struct {
+	epoch     int64
+	successes uint64
+	retries   uint64
+}
+
+// NewRetryBudget returns a new RetryBudget that allows retries up to the ratio
+// of successful operations within the sliding window duration.
+func NewRetryBudget(ratio float64, window time.Duration) *RetryBudget {
+	return &RetryBudget{
+		clock: clock.System(),
+		ratio: ratio,
+		width: max(window/retryBudgetBuckets, 1),
+	}
+}
+
+// WithClock returns a copy of the budget that uses the given clock. The
+// returned budget does not share the state with the original one.
+func (b *RetryBudget) WithClock(c *clock.Clock) *RetryBudget {
+	if c == nil {
+		c = clock.System()
+	}
+	return &RetryBudget{
+		clock:      c,
+		ratio:      b.ratio,
+		width:      b.width,
+		minRetries: b.minRetries,
+	}
+}
+
+// WithMinRetries returns a copy of the budget that allows at least n retries
+// within the sliding window regardless of the number of successful operations.
+// It allows retrying operations under low load. The returned budget does not
+// share the state with the original one.
+func (b *RetryBudget) WithMinRetries(n uint) *RetryBudget {
+	return &RetryBudget{
+		clock:      b.clock,
+		ratio:      b.ratio,
+		width:      b.width,
+		minRetries: n,
+	}
+}
+
+// Success records a successful operation.
+func (b *RetryBudget) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket(b.clock.Now()).successes++
+}
+
+// TryRetry returns whether a retry is allowed and, if so, records it.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	current := b.bucket(now)
+
+	var successes, retries uint64
+	for i := range b.buckets {
+		x := &b.buckets[i]
+		if current.epoch-x.epoch >= retryBudgetBuckets {
+			continue
+		}
+		successes += x.successes
+		retries += x.retries
+	}
+
+	limit := b.ratio*float64(successes) + float64(b.minRetries)
+	if float64(retries+1) > limit {
+		return false
+	}
+	current.retries++
+	return true
+}
+
+// bucket returns the bucket for the given time, resetting it if it is stale.
+func (b *RetryBudget) bucket(now time.Time) *retryBudgetBucket {
+	epoch := now.UnixNano() / int64(b.width)
+	i := epoch % retryBudgetBuckets
+	if i < 0 {
+		i += retryBudgetBuckets
+	}
+	x := &b.buckets[i]
+	if x.epoch != epoch {
+		*x = retryBudgetBucket{epoch: epoch}
+	}
+	return x
+}
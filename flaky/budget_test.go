@@ -0,0 +1,80 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+)
+
+func TestRetryBudget(t *testing.T) {
+	const window = 10 * time.Second
+
+	fakeClock := fakeclock.Unix()
+	budget := NewRetryBudget(0.5, window).WithClock(clock.NewClock(fakeClock))
+
+	if budget.TryRetry() {
+		t.Fatal("expected retry to be rejected without successful operations")
+	}
+
+	budget.Success()
+	budget.Success()
+	if !budget.TryRetry() {
+		t.Fatal("expected retry to be allowed")
+	}
+	if budget.TryRetry() {
+		t.Fatal("expected retry to be rejected once the budget is exhausted")
+	}
+
+	fakeClock.Add(window / 2)
+	budget.Success()
+	budget.Success()
+	if !budget.TryRetry() {
+		t.Fatal("expected retry to be allowed")
+	}
+
+	fakeClock.Add(window / 2)
+	budget.Success()
+	budget.Success()
+	if !budget.TryRetry() {
+		t.Fatal("expected retry to be allowed after old retries expire")
+	}
+
+	fakeClock.Add(window)
+	if budget.TryRetry() {
+		t.Fatal("expected retry to be rejected after successful operations expire")
+	}
+
+	budget = budget.WithMinRetries(1)
+	if !budget.TryRetry() {
+		t.Fatal("expected retry to be allowed with minimum retries")
+	}
+}
+
+func TestRetryWithBudget(t *testing.T) {
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	budget := NewRetryBudget(0.1, time.Minute).WithClock(clock.NewClock(fakeclock.Unix()))
+	executor := Retry(Constant(time.Second)).
+		WithClock(clock.NewClock(fakeclock.Unix())).
+		WithBudget(budget)
+
+	var n int
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		n++
+		return oops
+	})
+	if _, ok := errors.AsType[*RetryBudgetError](err); !ok {
+		t.Fatalf("expected retry budget error, got %v", err)
+	}
+	if !errors.Is(err, oops) {
+		t.Fatalf("expected error %v, got %v", oops, err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
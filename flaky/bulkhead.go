@@ -0,0 +1,57 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBulkheadFull is an error that BulkheadExecutor returns when an operation
+// is rejected because both the concurrency limit and the wait queue are full.
+var ErrBulkheadFull = errors.New("flaky: bulkhead is full")
+
+// BulkheadExecutor is an executor that limits the number of concurrently
+// executing operations. It allows protecting shared resources, e.g. connection
+// pools, from a large number of concurrent requests.
+//
+// Calls that exceed the concurrency limit wait in a bounded queue until an
+// operation completes or the context expires. If the queue is full, Execute
+// returns ErrBulkheadFull without waiting.
+//
+// BulkheadExecutor executes an operation at most once per Execute call. Use it
+// under other executors, e.g. Retry, to limit the concurrency of each attempt.
+type BulkheadExecutor struct {
+	// sem limits the number of executing operations.
+	sem chan struct{}
+	// tickets limits the number of executing and waiting operations.
+	tickets chan struct{}
+}
+
+// Bulkhead returns a new BulkheadExecutor that executes at most limit
+// operations concurrently and allows at most queue calls to wait for
+// execution. Passing zero limit is equivalent to one.
+func Bulkhead(limit, queue uint) *BulkheadExecutor {
+	limit = max(limit, 1)
+	return &BulkheadExecutor{
+		sem:     make(chan struct{}, limit),
+		tickets: make(chan struct{}, limit+queue),
+	}
+}
+
+// Execute implements the Executor interface.
+func (b *BulkheadExecutor) Execute(ctx context.Context, f Op) error {
+	select {
+	case b.tickets <- struct{}{}:
+	default:
+		return ErrBulkheadFull
+	}
+	defer func() { <-b.tickets }()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case b.sem <- struct{}{}:
+	}
+	defer func() { <-b.sem }()
+
+	return unwrapInternal(f(ctx))
+}
@@ -0,0 +1,67 @@
+package flaky
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestBulkhead(t *testing.T) {
+	ctx := context.Background()
+
+	bulkhead := Bulkhead(1, 1)
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		_ = bulkhead.Execute(ctx, func(_ context.Context) error {
+			close(running)
+			<-release
+			return nil
+		})
+	})
+	<-running
+
+	queuedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queuedErr := make(chan error, 1)
+	go func() {
+		queuedErr <- bulkhead.Execute(queuedCtx, func(_ context.Context) error {
+			panic("canceled operation should not be executed")
+		})
+	}()
+	for len(bulkhead.tickets) < 2 {
+		runtime.Gosched()
+	}
+
+	err := bulkhead.Execute(ctx, func(_ context.Context) error {
+		panic("rejected operation should not be executed")
+	})
+	if err != ErrBulkheadFull {
+		t.Fatalf("expected %v, got %v", ErrBulkheadFull, err)
+	}
+
+	cancel()
+	if err := <-queuedErr; err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	var n int
+	err = bulkhead.Execute(ctx, func(_ context.Context) error {
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
@@ -0,0 +1,209 @@
+package flaky
+
+import (
+	"time"
+)
+
+// maxFilterIterations is the maximum number of times filtering schedules
+// request the next time from the underlying schedule before giving up.
+const maxFilterIterations = 1024
+
+type earliestSchedule []Schedule
+
+// Earliest returns a Schedule that returns the earliest next time of the given
+// schedules. Schedules without the next time are ignored.
+func Earliest(ss ...Schedule) Schedule {
+	return earliestSchedule(ss)
+}
+
+// Next implements the Schedule interface.
+func (s earliestSchedule) Next(now time.Time) time.Time {
+	var next time.Time
+	for _, sched := range s {
+		t := sched.Next(now)
+		if t.IsZero() || t.Before(now) {
+			continue
+		}
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+type latestSchedule []Schedule
+
+// Latest returns a Schedule that returns the latest next time of the given
+// schedules. If any of the schedules has no next time, neither does the
+// returned schedule.
+func Latest(ss ...Schedule) Schedule {
+	return latestSchedule(ss)
+}
+
+// Next implements the Schedule interface.
+func (s latestSchedule) Next(now time.Time) time.Time {
+	var next time.Time
+	for _, sched := range s {
+		t := sched.Next(now)
+		if t.IsZero() || t.Before(now) {
+			return time.Time{}
+		}
+		if t.After(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+type offsetSchedule struct {
+	sched Schedule
+	d     time.Duration
+}
+
+// Offset returns a Schedule that shifts the times of the given schedule by the
+// duration d. For example, Offset(Every(time.Hour, nil), 5*time.Minute) runs at
+// five minutes past every hour.
+func Offset(s Schedule, d time.Duration) Schedule {
+	return &offsetSchedule{
+		sched: s,
+		d:     d,
+	}
+}
+
+// Next implements the Schedule interface.
+func (s *offsetSchedule) Next(now time.Time) time.Time {
+	next := s.sched.Next(now.Add(-s.d))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.d)
+}
+
+type everySchedule struct {
+	d   time.Duration
+	loc *time.Location
+}
+
+// Every returns a repeated Schedule for the interval d aligned to wall clock
+// boundaries since midnight in the given location. If the location is nil, it
+// defaults to UTC. For example, Every(15*time.Minute, nil) runs at 00, 15, 30
+// and 45 minutes past every hour.
+//
+// The schedule restarts at each midnight. If d does not divide 24 hours, the
+// last interval of the day is shorter.
+//
+// Passing a non-positive duration is equivalent to not using a schedule.
+func Every(d time.Duration, loc *time.Location) Schedule {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &everySchedule{d, loc}
+}
+
+// Next implements the Schedule interface.
+func (s *everySchedule) Next(now time.Time) time.Time {
+	if s.d <= 0 {
+		return now
+	}
+
+	nowLoc := now.Location()
+	now = now.In(s.loc)
+
+	midnight := startOfDay(now)
+	elapsed := now.Sub(midnight)
+
+	k := elapsed / s.d
+	if elapsed%s.d != 0 {
+		k++
+	}
+
+	next := midnight.Add(k * s.d)
+	if tomorrow := startOfDay(midnight.AddDate(0, 0, 1)); next.After(tomorrow) {
+		next = tomorrow
+	}
+	return next.In(nowLoc)
+}
+
+type filterSchedule struct {
+	sched Schedule
+	// skip returns zero time if t is accepted or the time to continue
+	// searching from otherwise.
+	skip func(t time.Time) time.Time
+}
+
+// Next implements the Schedule interface.
+func (s *filterSchedule) Next(now time.Time) time.Time {
+	from := now
+	for range maxFilterIterations {
+		next := s.sched.Next(from)
+		if next.IsZero() || next.Before(from) {
+			return time.Time{}
+		}
+		from = s.skip(next)
+		if from.IsZero() {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// Weekdays restricts the schedule to the given days of the week in the given
+// location. If the location is nil, it defaults to UTC.
+func Weekdays(s Schedule, loc *time.Location, days ...time.Weekday) Schedule {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var mask uint8
+	for _, d := range days {
+		mask |= 1 << d
+	}
+	return &filterSchedule{
+		sched: s,
+		skip: func(t time.Time) time.Time {
+			t = t.In(loc)
+			if mask&(1<<t.Weekday()) != 0 {
+				return time.Time{}
+			}
+			return startOfDay(t.AddDate(0, 0, 1))
+		},
+	}
+}
+
+// Between restricts the schedule to the daily window [start; end) where start
+// and end are durations since midnight in the given location. If the location
+// is nil, it defaults to UTC. If start is after end, the window spans midnight.
+// For example, Between(Every(15*time.Minute, nil), 9*time.Hour, 17*time.Hour,
+// nil) runs every 15 minutes during business hours.
+func Between(s Schedule, start, end time.Duration, loc *time.Location) Schedule {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &filterSchedule{
+		sched: s,
+		skip: func(t time.Time) time.Time {
+			t = t.In(loc)
+			midnight := startOfDay(t)
+			off := t.Sub(midnight)
+
+			var ok bool
+			if start <= end {
+				ok = off >= start && off < end
+			} else {
+				ok = off >= start || off < end
+			}
+			if ok {
+				return time.Time{}
+			}
+
+			if off < start {
+				return midnight.Add(start)
+			}
+			return startOfDay(midnight.AddDate(0, 0, 1)).Add(start)
+		},
+	}
+}
+
+// startOfDay returns the midnight of the day of t in t’s location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
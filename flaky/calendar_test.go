@@ -0,0 +1,107 @@
+package flaky
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleCombinators(t *testing.T) {
+	// Friday, 2038-01-15.
+	date := func(day, hour, minute int) time.Time {
+		return time.Date(2038, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	businessHours := Weekdays(
+		Between(Every(15*time.Minute, nil), 9*time.Hour, 17*time.Hour, nil),
+		nil,
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+	)
+
+	testCases := []struct {
+		Name     string
+		Schedule Schedule
+		Now      time.Time
+		Next     time.Time
+	}{
+		{
+			Name:     "Every",
+			Schedule: Every(15*time.Minute, nil),
+			Now:      date(15, 10, 7),
+			Next:     date(15, 10, 15),
+		},
+		{
+			Name:     "EveryAligned",
+			Schedule: Every(15*time.Minute, nil),
+			Now:      date(15, 10, 15),
+			Next:     date(15, 10, 15),
+		},
+		{
+			Name:     "EveryMidnight",
+			Schedule: Every(7*time.Hour, nil),
+			Now:      date(15, 22, 0),
+			Next:     date(16, 0, 0),
+		},
+		{
+			Name:     "Offset",
+			Schedule: Offset(Every(time.Hour, nil), 5*time.Minute),
+			Now:      date(15, 10, 7),
+			Next:     date(15, 11, 5),
+		},
+		{
+			Name:     "Earliest",
+			Schedule: Earliest(Sleep(time.Hour), Every(15*time.Minute, nil), Until(Sleep(time.Minute), date(1, 0, 0))),
+			Now:      date(15, 10, 7),
+			Next:     date(15, 10, 15),
+		},
+		{
+			Name:     "Latest",
+			Schedule: Latest(Sleep(time.Hour), Every(15*time.Minute, nil)),
+			Now:      date(15, 10, 7),
+			Next:     date(15, 11, 7),
+		},
+		{
+			Name:     "LatestNoNext",
+			Schedule: Latest(Sleep(time.Hour), Until(Sleep(time.Minute), date(1, 0, 0))),
+			Now:      date(15, 10, 7),
+			Next:     time.Time{},
+		},
+		{
+			Name:     "BusinessHours",
+			Schedule: businessHours,
+			Now:      date(15, 10, 7),
+			Next:     date(15, 10, 15),
+		},
+		{
+			Name:     "BusinessHoursBefore",
+			Schedule: businessHours,
+			Now:      date(15, 3, 0),
+			Next:     date(15, 9, 0),
+		},
+		{
+			Name:     "BusinessHoursWeekend",
+			Schedule: businessHours,
+			Now:      date(15, 17, 0),
+			Next:     date(18, 9, 0),
+		},
+		{
+			Name:     "BetweenMidnight",
+			Schedule: Between(Every(time.Hour, nil), 22*time.Hour, 2*time.Hour, nil),
+			Now:      date(15, 12, 30),
+			Next:     date(15, 22, 0),
+		},
+		{
+			Name:     "BetweenMidnightInside",
+			Schedule: Between(Every(time.Hour, nil), 22*time.Hour, 2*time.Hour, nil),
+			Now:      date(16, 0, 30),
+			Next:     date(16, 1, 0),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			next := tc.Schedule.Next(tc.Now)
+			if !next.Equal(tc.Next) {
+				t.Fatalf("expected %v, got %v", tc.Next, next)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// Classification is the result of classifying an error returned from an
+// operation attempt.
+type Classification uint8
+
+const (
+	// Unclassified indicates that the classifier has no opinion on whether
+	// the operation should be retried.
+	Unclassified Classification = iota
+	// Retryable indicates that the operation should be retried.
+	Retryable
+	// NonRetryable indicates that the operation should not be retried.
+	NonRetryable
+)
+
+// String implements the fmt.Stringer interface.
+func (c Classification) String() string {
+	switch c {
+	case Unclassified:
+		return "unclassified"
+	case Retryable:
+		return "retryable"
+	case NonRetryable:
+		return "non-retryable"
+	default:
+		return "Classification(" + strconv.Itoa(int(c)) + ")"
+	}
+}
+
+// Classifier is a function that classifies an error returned from an operation
+// attempt. It allows declaring a retry policy once instead of marking errors as
+// permanent at each call site.
+//
+// Note that permanent errors stop retries regardless of classification.
+type Classifier func(err error) Classification
+
+// Classifiers returns a Classifier that returns the first classification other
+// than Unclassified from the given classifiers.
+func Classifiers(cs ...Classifier) Classifier {
+	return func(err error) Classification {
+		for _, c := range cs {
+			if r := c(err); r != Unclassified {
+				return r
+			}
+		}
+		return Unclassified
+	}
+}
+
+// Always returns a Classifier that always returns the given classification. It
+// is useful as the last classifier to override the default classification of
+// unknown errors.
+func Always(c Classification) Classifier {
+	return func(error) Classification {
+		return c
+	}
+}
+
+// ContextError is a Classifier that classifies context cancellation and
+// deadline errors as non-retryable.
+func ContextError(err error) Classification {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return NonRetryable
+	}
+	return Unclassified
+}
+
+// NetTimeout is a Classifier that classifies network timeout errors as
+// retryable. It does not classify context deadline errors that also implement
+// the net.Error interface.
+func NetTimeout(err error) Classification {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Unclassified
+	}
+	if e, ok := errors.AsType[net.Error](err); ok && e.Timeout() {
+		return Retryable
+	}
+	return Unclassified
+}
+
+// SQLStater is the interface implemented by database errors that carry the
+// SQLSTATE code, e.g. *pgconn.PgError.
+type SQLStater interface {
+	error
+
+	// SQLState returns the SQLSTATE error code.
+	SQLState() string
+}
+
+// SerializationFailure is a Classifier that classifies PostgreSQL
+// serialization failure (40001) and deadlock detected (40P01) errors as
+// retryable. Such errors indicate that the transaction should be retried.
+func SerializationFailure(err error) Classification {
+	e, ok := errors.AsType[SQLStater](err)
+	if !ok {
+		return Unclassified
+	}
+	switch e.SQLState() {
+	case "40001", "40P01":
+		return Retryable
+	}
+	return Unclassified
+}
+
+// StatusCoder is the interface implemented by errors that carry an HTTP
+// response status code.
+type StatusCoder interface {
+	error
+
+	// StatusCode returns the HTTP response status code.
+	StatusCode() int
+}
+
+// HTTPStatus is a Classifier for errors that implement the StatusCoder
+// interface. See ClassifyHTTPStatus for the classification rules.
+func HTTPStatus(err error) Classification {
+	e, ok := errors.AsType[StatusCoder](err)
+	if !ok {
+		return Unclassified
+	}
+	return ClassifyHTTPStatus(e.StatusCode())
+}
+
+// ClassifyHTTPStatus classifies the HTTP response status code by its family.
+// Server errors (5xx), except 501 Not Implemented and 505 HTTP Version Not
+// Supported, as well as 408 Request Timeout, 425 Too Early and 429 Too Many
+// Requests are retryable. Other client errors (4xx) are non-retryable. Other
+// status codes are unclassified.
+func ClassifyHTTPStatus(code int) Classification {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return Retryable
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return NonRetryable
+	}
+	switch code / 100 {
+	case 4:
+		return NonRetryable
+	case 5:
+		return Retryable
+	}
+	return Unclassified
+}
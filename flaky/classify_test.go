@@ -0,0 +1,80 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+type statusCodeError int
+
+func (e statusCodeError) Error() string   { return fmt.Sprintf("HTTP %d", int(e)) }
+func (e statusCodeError) StatusCode() int { return int(e) }
+
+func TestClassifiers(t *testing.T) {
+	classify := Classifiers(
+		ContextError,
+		NetTimeout,
+		SerializationFailure,
+		HTTPStatus,
+	)
+
+	testCases := []struct {
+		Error  error
+		Output Classification
+	}{
+		{errors.New("oops"), Unclassified},
+		{context.Canceled, NonRetryable},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), NonRetryable},
+		{os.ErrDeadlineExceeded, Retryable},
+		{sqlStateError("40001"), Retryable},
+		{sqlStateError("40P01"), Retryable},
+		{sqlStateError("23505"), Unclassified},
+		{statusCodeError(200), Unclassified},
+		{statusCodeError(404), NonRetryable},
+		{statusCodeError(429), Retryable},
+		{statusCodeError(501), NonRetryable},
+		{statusCodeError(503), Retryable},
+	}
+	for _, tc := range testCases {
+		if c := classify(tc.Error); c != tc.Output {
+			t.Errorf("%v: expected %v, got %v", tc.Error, tc.Output, c)
+		}
+	}
+
+	if c := Classifiers(classify, Always(NonRetryable))(errors.New("oops")); c != NonRetryable {
+		t.Errorf("expected %v, got %v", NonRetryable, c)
+	}
+}
+
+func TestRetryClassifier(t *testing.T) {
+	ctx := context.Background()
+	oops := statusCodeError(400)
+
+	executor := Retry(Constant(time.Second)).
+		WithClock(clock.NewClock(fakeclock.Unix())).
+		WithClassifier(HTTPStatus)
+
+	var n int
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		n++
+		return oops
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
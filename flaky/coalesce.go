@@ -0,0 +1,114 @@
+package flaky
+
+import (
+	"context"
+	"sync"
+)
+
+// CoalesceExecutor is an executor that coalesces concurrent Execute calls with
+// the same key. That is, only one operation is executing at a time for the key
+// and all concurrent callers receive its result. It allows avoiding duplicate
+// work when many callers request the same expensive operation, e.g. a token
+// refresh.
+//
+// The operation runs in its own goroutine with a context that is not canceled
+// when a caller’s context is canceled. Instead, each caller may stop waiting
+// independently. The operation’s context is canceled once all callers stop
+// waiting for the result, and subsequent calls start a new operation.
+type CoalesceExecutor struct {
+	mu    sync.Mutex
+	calls map[string]*coalesceCall
+}
+
+// coalesceCall is an in-flight operation for CoalesceExecutor.
+type coalesceCall struct {
+	done    chan struct{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// Coalesce returns a new CoalesceExecutor instance.
+func Coalesce() *CoalesceExecutor {
+	return &CoalesceExecutor{}
+}
+
+// Key returns an executor that coalesces operations with the given key.
+func (c *CoalesceExecutor) Key(key string) Executor {
+	return ExecutorFunc(func(ctx context.Context, f Op) error {
+		return c.execute(ctx, key, f)
+	})
+}
+
+// Execute implements the Executor interface. It coalesces operations with the
+// empty key. See also Key method.
+//
+// Note that the operation f of the first caller is executed and operations
+// passed by other concurrent callers are ignored.
+func (c *CoalesceExecutor) Execute(ctx context.Context, f Op) error {
+	return c.execute(ctx, "", f)
+}
+
+// execute executes an operation f for the key or waits for the in-flight
+// operation to complete.
+func (c *CoalesceExecutor) execute(ctx context.Context, key string, f Op) error {
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if !ok {
+		call = c.start(ctx, key, f)
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return unwrapInternal(call.err)
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+		c.forget(key, call)
+	}
+	c.mu.Unlock()
+
+	return ctx.Err()
+}
+
+// start starts a new operation for the key. It must be called with the lock
+// held.
+func (c *CoalesceExecutor) start(ctx context.Context, key string, f Op) *coalesceCall {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &coalesceCall{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	if c.calls == nil {
+		c.calls = make(map[string]*coalesceCall)
+	}
+	c.calls[key] = call
+
+	go func() {
+		defer cancel()
+		err := f(ctx)
+
+		c.mu.Lock()
+		call.err = err
+		c.forget(key, call)
+		c.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	return call
+}
+
+// forget removes the call for the key unless it was replaced by another call.
+// It must be called with the lock held.
+func (c *CoalesceExecutor) forget(key string, call *coalesceCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
@@ -0,0 +1,100 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCoalesceExecutor(t *testing.T) {
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	executor := Coalesce()
+	exec := executor.Key("token")
+
+	var n atomic.Int32
+	release := make(chan struct{})
+	op := func(_ context.Context) error {
+		n.Add(1)
+		<-release
+		return oops
+	}
+
+	const callers = 3
+
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			errs[i] = exec.Execute(ctx, op)
+		})
+	}
+	waitForWaiters(executor, "token", callers)
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != oops {
+			t.Errorf("caller %d: expected %v, got %v", i, oops, err)
+		}
+	}
+	if n := n.Load(); n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
+
+func TestCoalesceExecutorCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executor := Coalesce()
+
+	release := make(chan struct{})
+	op := func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- executor.Execute(ctx, op)
+	}()
+	waitForWaiters(executor, "", 1)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- executor.Execute(context.Background(), op)
+	}()
+	waitForWaiters(executor, "", 2)
+
+	cancel()
+	if err := <-canceled; err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func waitForWaiters(c *CoalesceExecutor, key string, n int) {
+	for {
+		c.mu.Lock()
+		call, ok := c.calls[key]
+		ok = ok && call.waiters == n
+		c.mu.Unlock()
+		if ok {
+			return
+		}
+		runtime.Gosched()
+	}
+}
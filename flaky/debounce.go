@@ -28,6 +28,7 @@ type DebounceExecutor struct {
 
 	c *clock.Clock
 	w time.Duration
+	o Observer
 
 	timer clock.Timer
 }
@@ -54,6 +55,7 @@ func (d *DebounceExecutor) clone() *DebounceExecutor {
 		debounceState: d.debounceState,
 		c:             d.c,
 		w:             d.w,
+		o:             d.o,
 		timer:         d.timer,
 	}
 }
@@ -78,6 +80,18 @@ func (d *DebounceExecutor) WithWait(t time.Duration) *DebounceExecutor {
 	return d
 }
 
+// WithObserver returns a copy of the executor that notifies the given observer
+// about debounce waits, operation executions and debounced calls. Passing nil
+// disables observation.
+//
+// It shares the underlying state with the original executor.
+func (d *DebounceExecutor) WithObserver(o Observer) *DebounceExecutor {
+	d = d.clone()
+	d.o = o
+	d.init()
+	return d
+}
+
 // Execute calls the function f if and only if Execute is not called again
 // during the debounce interval. Context expiration cancels an operation. It
 // returns ErrDebounced error if the given operation f was superseded by another
@@ -96,10 +110,10 @@ func (d *DebounceExecutor) Execute(ctx context.Context, f Op) error {
 	// Acquire a lock or steal it from an ongoing debounced Execute call.
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return observeGiveUp(ctx, d.o, ctx.Err())
 	case v := <-d.exec:
 		d.exec <- v
-		return ErrDebounced
+		return observeGiveUp(ctx, d.o, ErrDebounced)
 	case d.lock <- struct{}{}:
 	case d.steal <- struct{}{}:
 		// Wait until the Execute call we are stealing from stops the
@@ -108,6 +122,7 @@ func (d *DebounceExecutor) Execute(ctx context.Context, f Op) error {
 	}
 
 	// Reset timer for the debounce duration.
+	observeWait(ctx, d.o, d.w)
 	if d.timer == nil {
 		d.timer = d.c.Timer(d.w)
 	} else {
@@ -120,10 +135,10 @@ func (d *DebounceExecutor) Execute(ctx context.Context, f Op) error {
 	select {
 	case <-d.timer.C():
 		d.exec <- struct{}{}
-		err := f(ctx)
+		err := observeAttempt(ctx, d.o, d.c, 0, f)
 		<-d.exec
 		<-d.lock
-		return unwrapInternal(err)
+		return observeGiveUp(ctx, d.o, unwrapInternal(err))
 	case <-d.steal:
 		steal = true
 	case <-ctx.Done():
@@ -133,11 +148,11 @@ func (d *DebounceExecutor) Execute(ctx context.Context, f Op) error {
 	}
 	if steal {
 		d.next <- struct{}{}
-		return ErrDebounced
+		return observeGiveUp(ctx, d.o, ErrDebounced)
 	}
 
 	<-d.lock
-	return ctx.Err()
+	return observeGiveUp(ctx, d.o, ctx.Err())
 }
 
 // init initializes the internal debouncer state.
@@ -5,5 +5,6 @@ go 1.26.0
 require (
 	github.com/robfig/cron/v3 v3.0.1
 	go.pact.im/x/clock v0.0.21
+	go.pact.im/x/logs v0.0.21
 	go.uber.org/mock v0.6.0
 )
@@ -0,0 +1,129 @@
+package flaky
+
+import (
+	"context"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// HedgeExecutor is an executor that starts additional concurrent attempts of
+// an operation if the previous attempts have not completed within the delay
+// provided by the backoff. It allows reducing tail latency for idempotent
+// operations at the cost of additional load.
+type HedgeExecutor struct {
+	clock   *clock.Clock
+	backoff BackoffProvider
+}
+
+// Hedge returns a new executor that uses the backoff provider to start hedged
+// attempts of the operation.
+//
+// It starts the first attempt immediately and then starts a new attempt each
+// time the delay returned from the backoff function elapses, until a backoff
+// function returns false or an attempt succeeds. The first successful attempt
+// wins and contexts of other attempts are canceled. An attempt that returns a
+// permanent error also cancels the other attempts. Otherwise Execute returns
+// the last error once all attempts fail and no more attempts can be started.
+//
+// Execute does not return until all attempts return. Operations should respect
+// context cancellation to avoid blocking the caller after an attempt succeeds.
+//
+// It requests a new backoff function from provider for each Execute invocation.
+func Hedge(b BackoffProvider) *HedgeExecutor {
+	return &HedgeExecutor{
+		clock:   clock.System(),
+		backoff: b,
+	}
+}
+
+// WithClock returns a copy of the executor that uses the given clock.
+func (h *HedgeExecutor) WithClock(c *clock.Clock) *HedgeExecutor {
+	if c == nil {
+		c = clock.System()
+	}
+	return &HedgeExecutor{
+		clock:   c,
+		backoff: h.backoff,
+	}
+}
+
+// Execute implements the Executor interface.
+func (h *HedgeExecutor) Execute(ctx context.Context, f Op) error {
+	backoff := h.backoff.Backoff()
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error)
+
+	var inflight int
+	start := func() {
+		inflight++
+		go func() {
+			results <- f(attemptCtx)
+		}()
+	}
+
+	var n uint
+	var timer clock.Timer
+	var timerC <-chan time.Time
+	schedule := func() {
+		timerC = nil
+		d, ok := backoff(n)
+		if !ok {
+			return
+		}
+		n++
+		if !withinDeadline(ctx, d) {
+			return
+		}
+		if timer == nil {
+			timer = h.clock.Timer(d)
+		} else {
+			timer.Reset(d)
+		}
+		timerC = timer.C()
+	}
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	// wait cancels the remaining attempts and waits for them to return.
+	wait := func() {
+		cancel()
+		for ; inflight > 0; inflight-- {
+			<-results
+		}
+	}
+
+	start()
+	schedule()
+
+	done := ctx.Done()
+
+	var err error
+	for inflight > 0 || timerC != nil {
+		select {
+		case <-done:
+			done, timerC = nil, nil
+		case <-timerC:
+			start()
+			schedule()
+		case e := <-results:
+			inflight--
+			if e == nil {
+				wait()
+				return nil
+			}
+			if IsPermanentError(e) {
+				wait()
+				return unwrapInternal(e)
+			}
+			err = e
+		}
+	}
+	return err
+}
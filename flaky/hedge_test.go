@@ -0,0 +1,98 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func TestHedge(t *testing.T) {
+	const delay = time.Second
+
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, delay)
+	go func() {
+		<-observer
+		fakeClock.Add(delay)
+	}()
+
+	executor := Hedge(Limit(1, Constant(delay))).WithClock(clock.NewClock(observeClock))
+
+	var n atomic.Int32
+	var canceled atomic.Bool
+	err := executor.Execute(ctx, func(ctx context.Context) error {
+		if n.Add(1) > 1 {
+			return nil
+		}
+		<-ctx.Done()
+		canceled.Store(true)
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := n.Load(); n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+	if !canceled.Load() {
+		t.Fatal("expected slow attempt to be canceled")
+	}
+}
+
+func TestHedgeFailure(t *testing.T) {
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	executor := Hedge(Limit(0, Constant(time.Second))).
+		WithClock(clock.NewClock(fakeclock.Unix()))
+
+	var n int
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		n++
+		return oops
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
+
+func TestHedgePermanent(t *testing.T) {
+	const delay = time.Second
+
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, delay)
+	go func() {
+		<-observer
+		fakeClock.Add(delay)
+	}()
+
+	executor := Hedge(Constant(delay)).WithClock(clock.NewClock(observeClock))
+
+	var n atomic.Int32
+	err := executor.Execute(ctx, func(ctx context.Context) error {
+		if n.Add(1) > 1 {
+			return Internal(oops)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+}
@@ -107,3 +107,46 @@ func (j Jitter) Backoff(b Backoff, v JitterInterval) Backoff {
 		return d + j(v), ok
 	}
 }
+
+// FullJitter returns a backoff provider that returns a random delay in the
+// interval (0; d] where d is the delay returned from the given backoff. That is,
+// it implements the “full jitter” strategy.
+func FullJitter(j JitterProvider, b Backoff) BackoffProvider {
+	return BackoffProviderFunc(func() Backoff {
+		jitter := j.Jitter()
+		return func(n uint) (time.Duration, bool) {
+			d, ok := b(n)
+			if !ok {
+				return d, ok
+			}
+			return jitter(JitterInterval{L: d}), true
+		}
+	})
+}
+
+// DecorrelatedJitter returns a backoff provider that implements the
+// “decorrelated jitter” strategy. It returns a random delay in the interval
+// (base; 3×prev] capped to the ceil duration, where prev is the previous delay
+// or base for the first retry.
+//
+// Unlike other backoff functions, the delay depends on the previous delay and
+// hence the provider returns a stateful Backoff for each Execute invocation.
+func DecorrelatedJitter(j JitterProvider, base, ceil time.Duration) BackoffProvider {
+	return BackoffProviderFunc(func() Backoff {
+		jitter := j.Jitter()
+		prev := base
+		return func(_ uint) (time.Duration, bool) {
+			hi := ceil
+			if prev <= ceil/3 {
+				hi = prev * 3
+			}
+			d := base
+			if hi > base {
+				d += jitter(JitterInterval{L: hi - base})
+			}
+			d = min(d, ceil)
+			prev = d
+			return d, true
+		}
+	})
+}
@@ -63,3 +63,38 @@ func TestRandomJitter(t *testing.T) {
 		}
 	}
 }
+
+func TestFullJitter(t *testing.T) {
+	var last int64
+	j := RandomJitter(func(n int64) int64 {
+		last = n
+		return 0
+	})
+	b := FullJitter(j, Constant(time.Second)).Backoff()
+	d, ok := b(0)
+	if !ok || d != time.Nanosecond {
+		t.Fatalf("expected (1ns, true), got (%v, %v)", d, ok)
+	}
+	if last != int64(time.Second) {
+		t.Fatalf("expected interval length %d, got %d", int64(time.Second), last)
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	j := RandomJitter(func(n int64) int64 {
+		return n - 1
+	})
+	b := DecorrelatedJitter(j, time.Second, 20*time.Second).Backoff()
+	expected := []time.Duration{
+		3 * time.Second,
+		9 * time.Second,
+		20 * time.Second,
+		20 * time.Second,
+	}
+	for n, x := range expected {
+		d, ok := b(uint(n))
+		if !ok || d != x {
+			t.Errorf("n=%d: expected (%v, true), got (%v, %v)", n, x, d, ok)
+		}
+	}
+}
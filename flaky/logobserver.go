@@ -0,0 +1,61 @@
+package flaky
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.pact.im/x/logs"
+)
+
+// LogObserver is an Observer that logs executor events using logs.Logger.
+//
+// Attempt starts and waits are logged at debug level, failed attempts at warn
+// level and give-ups at error level.
+type LogObserver struct {
+	log *logs.Logger
+}
+
+// NewLogObserver returns a new LogObserver that uses the given logger.
+func NewLogObserver(l *logs.Logger) *LogObserver {
+	return &LogObserver{
+		log: l,
+	}
+}
+
+// AttemptStart implements the Observer interface.
+func (l *LogObserver) AttemptStart(ctx context.Context, n uint) {
+	l.log.Log(ctx, slog.LevelDebug, "Starting attempt",
+		slog.Uint64("attempt", uint64(n)),
+	)
+}
+
+// AttemptEnd implements the Observer interface.
+func (l *LogObserver) AttemptEnd(ctx context.Context, n uint, err error, d time.Duration) {
+	if err == nil {
+		l.log.Log(ctx, slog.LevelDebug, "Attempt succeeded",
+			slog.Uint64("attempt", uint64(n)),
+			slog.Duration("duration", d),
+		)
+		return
+	}
+	l.log.Log(ctx, slog.LevelWarn, "Attempt failed",
+		slog.Uint64("attempt", uint64(n)),
+		slog.Duration("duration", d),
+		slog.Any("error", err),
+	)
+}
+
+// Wait implements the Observer interface.
+func (l *LogObserver) Wait(ctx context.Context, d time.Duration) {
+	l.log.Log(ctx, slog.LevelDebug, "Waiting before next attempt",
+		slog.Duration("delay", d),
+	)
+}
+
+// GiveUp implements the Observer interface.
+func (l *LogObserver) GiveUp(ctx context.Context, err error) {
+	l.log.Log(ctx, slog.LevelError, "Giving up on operation",
+		slog.Any("error", err),
+	)
+}
@@ -0,0 +1,148 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// Observer observes operation execution by executors. It allows collecting
+// metrics and logging retries, waits and failures.
+//
+// Observer methods are called synchronously from the executor and may be
+// called concurrently. Implementations should return quickly.
+type Observer interface {
+	// AttemptStart is called before the nth attempt to execute an
+	// operation. Attempts are counted from zero.
+	AttemptStart(ctx context.Context, n uint)
+	// AttemptEnd is called after the nth attempt returns with the attempt
+	// error and duration.
+	AttemptEnd(ctx context.Context, n uint, err error, d time.Duration)
+	// Wait is called before the executor waits for the duration d, e.g.
+	// a backoff delay, scheduled time or debounce interval.
+	Wait(ctx context.Context, d time.Duration)
+	// GiveUp is called when the executor stops executing an operation
+	// with a non-nil error. It is not called if the operation was
+	// superseded with ErrDebounced or the context is done, even if the
+	// last attempt has failed with another error.
+	GiveUp(ctx context.Context, err error)
+}
+
+type multiObserver []Observer
+
+// Observers returns an Observer that calls each of the given observers in
+// order.
+func Observers(os ...Observer) Observer {
+	return multiObserver(os)
+}
+
+// AttemptStart implements the Observer interface.
+func (m multiObserver) AttemptStart(ctx context.Context, n uint) {
+	for _, o := range m {
+		o.AttemptStart(ctx, n)
+	}
+}
+
+// AttemptEnd implements the Observer interface.
+func (m multiObserver) AttemptEnd(ctx context.Context, n uint, err error, d time.Duration) {
+	for _, o := range m {
+		o.AttemptEnd(ctx, n, err, d)
+	}
+}
+
+// Wait implements the Observer interface.
+func (m multiObserver) Wait(ctx context.Context, d time.Duration) {
+	for _, o := range m {
+		o.Wait(ctx, d)
+	}
+}
+
+// GiveUp implements the Observer interface.
+func (m multiObserver) GiveUp(ctx context.Context, err error) {
+	for _, o := range m {
+		o.GiveUp(ctx, err)
+	}
+}
+
+// ExpvarObserver is an Observer that exposes counters in an expvar.Map.
+//
+// It maintains the following keys:
+//   - attempts is the number of started attempts;
+//   - failures is the number of attempts that returned an error;
+//   - waits is the number of waits;
+//   - wait_ns is the total wait duration in nanoseconds;
+//   - give_ups is the number of operations that executor gave up on.
+type ExpvarObserver struct {
+	m *expvar.Map
+}
+
+// NewExpvarObserver returns a new ExpvarObserver that adds counters to the
+// given map. Use expvar.NewMap to publish the map.
+func NewExpvarObserver(m *expvar.Map) *ExpvarObserver {
+	return &ExpvarObserver{m}
+}
+
+// AttemptStart implements the Observer interface.
+func (e *ExpvarObserver) AttemptStart(_ context.Context, _ uint) {
+	e.m.Add("attempts", 1)
+}
+
+// AttemptEnd implements the Observer interface.
+func (e *ExpvarObserver) AttemptEnd(_ context.Context, _ uint, err error, _ time.Duration) {
+	if err != nil {
+		e.m.Add("failures", 1)
+	}
+}
+
+// Wait implements the Observer interface.
+func (e *ExpvarObserver) Wait(_ context.Context, d time.Duration) {
+	e.m.Add("waits", 1)
+	e.m.Add("wait_ns", int64(d))
+}
+
+// GiveUp implements the Observer interface.
+func (e *ExpvarObserver) GiveUp(_ context.Context, _ error) {
+	e.m.Add("give_ups", 1)
+}
+
+// observeAttempt executes the nth attempt of f and notifies the observer, if
+// any, about attempt start and end.
+func observeAttempt(ctx context.Context, o Observer, c *clock.Clock, n uint, f Op) error {
+	if o == nil {
+		return f(ctx)
+	}
+	o.AttemptStart(ctx, n)
+	start := c.Now()
+	err := f(ctx)
+	o.AttemptEnd(ctx, n, err, c.Now().Sub(start))
+	return err
+}
+
+// observeWait notifies the observer, if any, about the wait for duration d.
+func observeWait(ctx context.Context, o Observer, d time.Duration) {
+	if o == nil {
+		return
+	}
+	o.Wait(ctx, d)
+}
+
+// observeGiveUp notifies the observer, if any, that an executor gives up with
+// a non-nil error and returns that error. Superseded operations and operations
+// stopped due to context cancellation are not reported since they are not
+// failures.
+func observeGiveUp(ctx context.Context, o Observer, err error) error {
+	if o == nil || err == nil {
+		return err
+	}
+	if errors.Is(err, ErrDebounced) {
+		return err
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+	o.GiveUp(ctx, err)
+	return err
+}
@@ -0,0 +1,263 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+	"go.pact.im/x/logs"
+)
+
+type recordObserver struct {
+	events []string
+}
+
+func (r *recordObserver) AttemptStart(_ context.Context, n uint) {
+	r.events = append(r.events, fmt.Sprintf("start %d", n))
+}
+
+func (r *recordObserver) AttemptEnd(_ context.Context, n uint, err error, d time.Duration) {
+	r.events = append(r.events, fmt.Sprintf("end %d %v %v", n, err, d))
+}
+
+func (r *recordObserver) Wait(_ context.Context, d time.Duration) {
+	r.events = append(r.events, fmt.Sprintf("wait %v", d))
+}
+
+func (r *recordObserver) GiveUp(_ context.Context, err error) {
+	r.events = append(r.events, fmt.Sprintf("give up %v", err))
+}
+
+func TestRetryObserver(t *testing.T) {
+	const backoff = time.Second
+
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, backoff)
+	go func() {
+		<-observer
+		fakeClock.Add(backoff)
+	}()
+
+	var rec recordObserver
+	vars := new(expvar.Map).Init()
+
+	var messages []string
+	log := logs.New(logs.HandlerFunc(func(_ context.Context, r slog.Record) error {
+		messages = append(messages, r.Message)
+		return nil
+	}))
+
+	executor := Retry(Limit(1, Constant(backoff))).
+		WithClock(clock.NewClock(observeClock)).
+		WithObserver(Observers(
+			&rec,
+			NewExpvarObserver(vars),
+			NewLogObserver(log),
+		))
+
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		return oops
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+
+	expected := []string{
+		"start 0",
+		"end 0 oops 0s",
+		"wait 1s",
+		"start 1",
+		"end 1 oops 0s",
+		"give up oops",
+	}
+	if !slices.Equal(rec.events, expected) {
+		t.Fatalf("expected events %q, got %q", expected, rec.events)
+	}
+
+	counters := map[string]string{
+		"attempts": "2",
+		"failures": "2",
+		"waits":    "1",
+		"wait_ns":  "1000000000",
+		"give_ups": "1",
+	}
+	for k, v := range counters {
+		if s := vars.Get(k).String(); s != v {
+			t.Errorf("expected %s==%s, got %s", k, v, s)
+		}
+	}
+
+	expectedMessages := []string{
+		"Starting attempt",
+		"Attempt failed",
+		"Waiting before next attempt",
+		"Starting attempt",
+		"Attempt failed",
+		"Giving up on operation",
+	}
+	if !slices.Equal(messages, expectedMessages) {
+		t.Fatalf("expected messages %q, got %q", expectedMessages, messages)
+	}
+}
+
+func TestRetryObserverCancel(t *testing.T) {
+	const backoff = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	oops := errors.New("oops")
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, backoff)
+	go func() {
+		<-observer
+		cancel()
+	}()
+
+	var rec recordObserver
+	executor := Retry(Constant(backoff)).
+		WithClock(clock.NewClock(observeClock)).
+		WithObserver(&rec)
+
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		return oops
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+
+	expected := []string{
+		"start 0",
+		"end 0 oops 0s",
+		"wait 1s",
+	}
+	if !slices.Equal(rec.events, expected) {
+		t.Fatalf("expected events %q, got %q", expected, rec.events)
+	}
+}
+
+func TestScheduleObserver(t *testing.T) {
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, time.Hour)
+	go func() {
+		<-observer
+		fakeClock.Add(time.Hour)
+	}()
+
+	var rec recordObserver
+	executor := WithSchedule(Once(), Sleep(time.Hour)).
+		WithClock(clock.NewClock(observeClock)).
+		WithObserver(&rec)
+
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"wait 1h0m0s",
+		"start 0",
+		"end 0 <nil> 0s",
+	}
+	if !slices.Equal(rec.events, expected) {
+		t.Fatalf("expected events %q, got %q", expected, rec.events)
+	}
+}
+
+func TestDebounceObserver(t *testing.T) {
+	const wait = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, wait)
+
+	var rec recordObserver
+	executor := Debounce(wait).
+		WithClock(clock.NewClock(observeClock)).
+		WithObserver(&rec)
+
+	op := func(_ context.Context) error {
+		t.Fatal("unexpected operation")
+		return nil
+	}
+
+	superseded := make(chan error)
+	go func() {
+		superseded <- executor.Execute(ctx, op)
+	}()
+	<-observer
+
+	done := make(chan error)
+	go func() {
+		done <- executor.Execute(ctx, op)
+	}()
+
+	if err := <-superseded; err != ErrDebounced {
+		t.Fatalf("expected %v, got %v", ErrDebounced, err)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	for _, event := range rec.events {
+		if strings.HasPrefix(event, "give up") {
+			t.Fatalf("unexpected give up event in %q", rec.events)
+		}
+	}
+}
+
+func TestWatchdogObserver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, time.Hour)
+	go func() {
+		<-observer
+		cancel()
+	}()
+
+	var rec recordObserver
+	executor := Watchdog(Once(), Sleep(time.Hour)).
+		WithClock(clock.NewClock(observeClock)).
+		WithObserver(&rec)
+
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"wait 1h0m0s",
+	}
+	if !slices.Equal(rec.events, expected) {
+		t.Fatalf("expected events %q, got %q", expected, rec.events)
+	}
+}
@@ -0,0 +1,131 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// ErrRateLimited is an error that RateLimitExecutor returns when an operation
+// is rejected because the rate limit is exceeded or waiting for the rate limit
+// would exceed the context deadline.
+var ErrRateLimited = errors.New("flaky: rate limit exceeded")
+
+// RateLimitExecutor is an executor that limits the rate of operations using a
+// token bucket. It allows capping the rate of requests to external services
+// across goroutines.
+//
+// The bucket holds at most burst tokens and is refilled with one token per
+// interval. Each operation consumes a token. When the bucket is empty, Execute
+// either waits for the next token or returns ErrRateLimited, depending on the
+// executor mode.
+//
+// RateLimitExecutor executes an operation at most once per Execute call. Use it
+// under Retry or WithSchedule to limit the rate of each attempt.
+type RateLimitExecutor struct {
+	clock    *clock.Clock
+	interval time.Duration
+	burst    uint
+	reject   bool
+
+	mu  sync.Mutex
+	tat time.Time
+}
+
+// RateLimit returns a new RateLimitExecutor that allows one operation per
+// interval on average with bursts of up to burst operations. Passing zero
+// burst is equivalent to one. By default, Execute waits until the operation
+// is allowed to run.
+func RateLimit(interval time.Duration, burst uint) *RateLimitExecutor {
+	return &RateLimitExecutor{
+		clock:    clock.System(),
+		interval: interval,
+		burst:    max(burst, 1),
+	}
+}
+
+// WithClock returns a copy of the executor that uses the given clock. The
+// returned executor has a full token bucket.
+func (r *RateLimitExecutor) WithClock(c *clock.Clock) *RateLimitExecutor {
+	if c == nil {
+		c = clock.System()
+	}
+	return &RateLimitExecutor{
+		clock:    c,
+		interval: r.interval,
+		burst:    r.burst,
+		reject:   r.reject,
+	}
+}
+
+// WithReject returns a copy of the executor that returns ErrRateLimited instead
+// of waiting if the rate limit is exceeded. The returned executor has a full
+// token bucket.
+func (r *RateLimitExecutor) WithReject(reject bool) *RateLimitExecutor {
+	return &RateLimitExecutor{
+		clock:    r.clock,
+		interval: r.interval,
+		burst:    r.burst,
+		reject:   reject,
+	}
+}
+
+// Execute implements the Executor interface.
+func (r *RateLimitExecutor) Execute(ctx context.Context, f Op) error {
+	tat, d, ok := r.reserve(ctx)
+	if !ok {
+		return ErrRateLimited
+	}
+	if d > 0 {
+		timer := r.clock.Timer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			r.cancel(tat)
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+	return unwrapInternal(f(ctx))
+}
+
+// reserve reserves a token and returns the duration to wait before executing an
+// operation. It returns false if the token cannot be reserved.
+//
+// Internally, it uses generic cell rate algorithm that keeps track of the
+// theoretical arrival time of the next operation.
+func (r *RateLimitExecutor) reserve(ctx context.Context) (time.Time, time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+
+	tat := r.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	tat = tat.Add(r.interval)
+
+	d := tat.Sub(now) - time.Duration(r.burst)*r.interval
+	if d > 0 && (r.reject || !withinDeadline(ctx, d)) {
+		return time.Time{}, 0, false
+	}
+
+	r.tat = tat
+	return tat, d, true
+}
+
+// cancel returns a token reserved with the given theoretical arrival time if
+// no other reservations were made since then.
+func (r *RateLimitExecutor) cancel(tat time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.tat.Equal(tat) {
+		return
+	}
+	r.tat = tat.Add(-r.interval)
+}
@@ -0,0 +1,116 @@
+package flaky
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func TestRateLimitReject(t *testing.T) {
+	const interval = time.Second
+
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	limiter := RateLimit(interval, 2).
+		WithClock(clock.NewClock(fakeClock)).
+		WithReject(true)
+
+	var n int
+	op := func(_ context.Context) error {
+		n++
+		return nil
+	}
+
+	for range 2 {
+		if err := limiter.Execute(ctx, op); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := limiter.Execute(ctx, op); err != ErrRateLimited {
+		t.Fatalf("expected %v, got %v", ErrRateLimited, err)
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+
+	fakeClock.Add(interval)
+	if err := limiter.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := limiter.Execute(ctx, op); err != ErrRateLimited {
+		t.Fatalf("expected %v, got %v", ErrRateLimited, err)
+	}
+	if n != 3 {
+		t.Fatalf("expected n==3, got %d", n)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	const interval = time.Second
+
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, interval)
+	go func() {
+		<-observer
+		fakeClock.Add(interval)
+	}()
+
+	limiter := RateLimit(interval, 1).WithClock(clock.NewClock(observeClock))
+
+	var n int
+	op := func(_ context.Context) error {
+		n++
+		return nil
+	}
+	for range 2 {
+		if err := limiter.Execute(ctx, op); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+}
+
+func TestRateLimitCancel(t *testing.T) {
+	const interval = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveNext()
+	go func() {
+		<-observer
+		cancel()
+	}()
+
+	limiter := RateLimit(interval, 1).WithClock(clock.NewClock(observeClock))
+
+	op := func(_ context.Context) error {
+		return nil
+	}
+	if err := limiter.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := limiter.Execute(ctx, func(_ context.Context) error {
+		panic("canceled operation should not be executed")
+	})
+	if err != ctx.Err() {
+		t.Fatalf("expected ctx.Err()==err, got err=%v, ctx.Err()=%v", err, ctx.Err())
+	}
+
+	// Canceled reservation is returned to the bucket.
+	if tat := time.Unix(0, 0).Add(interval); !limiter.tat.Equal(tat) {
+		t.Fatalf("expected tat==%v, got %v", tat, limiter.tat)
+	}
+}
@@ -2,17 +2,28 @@ package flaky
 
 import (
 	"context"
+	"errors"
 	"math"
+	"time"
 
 	"go.pact.im/x/clock"
 )
 
+// ErrAttemptTimeout is an error that RetryExecutor returns from an attempt that
+// exceeds the per-attempt timeout. Unlike the parent context expiration, such
+// attempts are retried.
+var ErrAttemptTimeout = errors.New("flaky: attempt timed out")
+
 // RetryExecutor is an executor that retries executing an operation using the
 // provided backoff. It allows executing operations that may succeed after a few
 // attempts.
 type RetryExecutor struct {
-	clock   *clock.Clock
-	backoff BackoffProvider
+	clock    *clock.Clock
+	backoff  BackoffProvider
+	classify Classifier
+	observer Observer
+	budget   *RetryBudget
+	timeout  time.Duration
 }
 
 // Retry returns a new executor that uses the backoff provider to retry
@@ -22,6 +33,10 @@ type RetryExecutor struct {
 // returns permanent error or a context expires. On failure it returns the last
 // error encountered.
 //
+// If an attempt returns an error that implements the RetryAfterer interface
+// (e.g. RetryAfterError), it waits for at least the requested delay before the
+// next attempt. It stops if the delay exceeds the context deadline.
+//
 // It requests a new backoff function from provider for each Execute invocation.
 func Retry(b BackoffProvider) *RetryExecutor {
 	return &RetryExecutor{
@@ -35,25 +50,75 @@ func (r *RetryExecutor) WithClock(c *clock.Clock) *RetryExecutor {
 	if c == nil {
 		c = clock.System()
 	}
-	return &RetryExecutor{
-		clock:   c,
-		backoff: r.backoff,
-	}
+	rc := *r
+	rc.clock = c
+	return &rc
+}
+
+// WithClassifier returns a copy of the executor that uses the given classifier
+// to decide whether an operation should be retried. It stops retrying if the
+// classifier returns NonRetryable for an error. Unclassified errors are
+// retried. Passing nil classifier resets the executor to the default behavior
+// of retrying all errors except permanent errors.
+func (r *RetryExecutor) WithClassifier(c Classifier) *RetryExecutor {
+	rc := *r
+	rc.classify = c
+	return &rc
+}
+
+// WithBudget returns a copy of the executor that uses the given retry budget.
+// The executor records successful attempts in the budget and stops retrying
+// with RetryBudgetError once the budget is exhausted. Passing nil disables the
+// retry budget.
+//
+// The same budget may be shared between multiple executors.
+func (r *RetryExecutor) WithBudget(b *RetryBudget) *RetryExecutor {
+	rc := *r
+	rc.budget = b
+	return &rc
+}
+
+// WithAttemptTimeout returns a copy of the executor that cancels the context of
+// each attempt after the timeout d. An attempt that fails after the timeout
+// expires returns ErrAttemptTimeout that is retried as usual. Passing
+// non-positive duration disables the per-attempt timeout.
+//
+// Note that expiration of the context passed to Execute is not an attempt
+// timeout and stops retries.
+func (r *RetryExecutor) WithAttemptTimeout(d time.Duration) *RetryExecutor {
+	rc := *r
+	rc.timeout = d
+	return &rc
+}
+
+// WithObserver returns a copy of the executor that notifies the given observer
+// about attempts, backoff waits and give-ups. Passing nil disables observation.
+func (r *RetryExecutor) WithObserver(o Observer) *RetryExecutor {
+	rc := *r
+	rc.observer = o
+	return &rc
 }
 
 // Execute implements the Executor interface.
 func (r *RetryExecutor) Execute(ctx context.Context, f Op) error {
 	backoff := r.backoff.Backoff()
+	attempt := r.attempt(f)
 
 	var err error
 	var timer clock.Timer
 	for n := range uint(math.MaxUint) {
-		err = f(ctx)
+		err = observeAttempt(ctx, r.observer, r.clock, n, attempt)
 		if err == nil {
+			if r.budget != nil {
+				r.budget.Success()
+			}
 			return nil
 		}
 		if IsPermanentError(err) {
-			return unwrapInternal(err)
+			return observeGiveUp(ctx, r.observer, unwrapInternal(err))
+		}
+		if r.classify != nil && r.classify(err) == NonRetryable {
+			break
 		}
 
 		if ctx.Err() != nil {
@@ -64,11 +129,19 @@ func (r *RetryExecutor) Execute(ctx context.Context, f Op) error {
 		if !ok {
 			break
 		}
+		if m, ok := RetryAfterDelay(err); ok && m > d {
+			d = m
+		}
 
 		if !withinDeadline(ctx, d) {
 			break
 		}
 
+		if r.budget != nil && !r.budget.TryRetry() {
+			return observeGiveUp(ctx, r.observer, &RetryBudgetError{err})
+		}
+
+		observeWait(ctx, r.observer, d)
 		if timer == nil {
 			timer = r.clock.Timer(d)
 			defer timer.Stop()
@@ -77,10 +150,32 @@ func (r *RetryExecutor) Execute(ctx context.Context, f Op) error {
 		}
 		select {
 		case <-ctx.Done():
-			return err
+			return observeGiveUp(ctx, r.observer, err)
 		case <-timer.C():
 			continue
 		}
 	}
-	return err
+	return observeGiveUp(ctx, r.observer, err)
+}
+
+// attempt returns the operation f with per-attempt timeout, if any.
+func (r *RetryExecutor) attempt(f Op) Op {
+	if r.timeout <= 0 {
+		return f
+	}
+	return func(ctx context.Context) error {
+		attemptCtx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		event := r.clock.Schedule(r.timeout, func(time.Time) {
+			cancel(ErrAttemptTimeout)
+		})
+		defer event.Stop()
+
+		err := f(attemptCtx)
+		if err != nil && ctx.Err() == nil && context.Cause(attemptCtx) == ErrAttemptTimeout {
+			return ErrAttemptTimeout
+		}
+		return err
+	}
 }
@@ -9,7 +9,9 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
 	"go.pact.im/x/clock/mockclock"
+	"go.pact.im/x/clock/observeclock"
 )
 
 func TestRetry(t *testing.T) {
@@ -108,3 +110,66 @@ func TestRetry(t *testing.T) {
 		}
 	})
 }
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	const (
+		timeout = time.Minute
+		backoff = time.Second
+	)
+
+	ctx := context.Background()
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	timeoutObserver := observeClock.ObserveKind(observeclock.KindSchedule, timeout)
+	backoffObserver := observeClock.ObserveKind(observeclock.KindTimer, backoff)
+	go func() {
+		<-timeoutObserver
+		fakeClock.Add(timeout)
+		<-backoffObserver
+		fakeClock.Add(backoff)
+	}()
+
+	executor := Retry(Limit(1, Constant(backoff))).
+		WithClock(clock.NewClock(observeClock)).
+		WithAttemptTimeout(timeout).
+		WithClassifier(ContextError)
+
+	var n int
+	err := executor.Execute(ctx, func(ctx context.Context) error {
+		n++
+		if n > 1 {
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+}
+
+func TestRetryAttemptTimeoutParent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executor := Retry(Constant(time.Second)).
+		WithClock(clock.NewClock(fakeclock.Unix())).
+		WithAttemptTimeout(time.Minute)
+
+	var n int
+	err := executor.Execute(ctx, func(ctx context.Context) error {
+		n++
+		cancel()
+		return ctx.Err()
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
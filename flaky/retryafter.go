@@ -0,0 +1,87 @@
+package flaky
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfterer is the interface implemented by errors that carry a minimum
+// delay before the next attempt, e.g. a delay requested by the server.
+type RetryAfterer interface {
+	error
+
+	// RetryAfter returns the minimum delay before the next attempt.
+	RetryAfter() time.Duration
+}
+
+// RetryAfterError is an error with a minimum delay before the next attempt.
+// RetryExecutor waits for at least that delay before retrying an operation.
+type RetryAfterError struct {
+	err error
+	d   time.Duration
+}
+
+// RetryAfter marks an error as requiring at least the duration d before the
+// next attempt. Negative duration is equivalent to zero.
+func RetryAfter(err error, d time.Duration) *RetryAfterError {
+	return &RetryAfterError{err, max(d, 0)}
+}
+
+// RetryAfterDelay attempts to extract the minimum delay before the next attempt
+// from err’s error chain. It returns false if the error chain does not contain
+// an error that implements the RetryAfterer interface.
+func RetryAfterDelay(err error) (time.Duration, bool) {
+	e, ok := errors.AsType[RetryAfterer](err)
+	if !ok {
+		return 0, false
+	}
+	return e.RetryAfter(), true
+}
+
+// Error implements the error interface.
+func (e *RetryAfterError) Error() string {
+	return "retry after " + e.d.String() + ": " + e.err.Error()
+}
+
+// Unwrap unwraps the underlying error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter implements the RetryAfterer interface.
+func (e *RetryAfterError) RetryAfter() time.Duration {
+	return e.d
+}
+
+// ResponseRetryAfter returns the delay specified in the Retry-After header of
+// the HTTP response. It returns false if the header is missing or invalid.
+//
+// The header value is either a number of seconds or an HTTP date. In the latter
+// case, the delay is relative to the Date header of the response if it is
+// present and valid, or to the given now time otherwise. A date in the past
+// results in zero delay.
+func ResponseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+
+	if n, err := strconv.ParseUint(v, 10, 63); err == nil {
+		if n > uint64(maxDuration/time.Second) {
+			return maxDuration, true
+		}
+		return time.Duration(n) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		now = date
+	}
+	return max(t.Sub(now), 0), true
+}
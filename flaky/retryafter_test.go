@@ -0,0 +1,116 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func TestRetryAfter(t *testing.T) {
+	const delay = 5 * time.Second
+
+	ctx := context.Background()
+	oops := errors.New("oops")
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	observer := observeClock.ObserveKind(observeclock.KindTimer, delay)
+	go func() {
+		<-observer
+		fakeClock.Add(delay)
+	}()
+
+	executor := Retry(Constant(time.Second)).WithClock(clock.NewClock(observeClock))
+
+	var n int
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		n++
+		if n == 1 {
+			return RetryAfter(oops, delay)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+}
+
+func TestRetryAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	oops := RetryAfter(errors.New("oops"), 2*time.Hour)
+
+	executor := Retry(Constant(time.Second)).WithClock(clock.NewClock(fakeclock.Unix()))
+
+	var n int
+	err := executor.Execute(ctx, func(_ context.Context) error {
+		n++
+		return oops
+	})
+	if err != oops {
+		t.Fatalf("expected %v, got %v", oops, err)
+	}
+	if n != 1 {
+		t.Fatalf("expected n==1, got %d", n)
+	}
+}
+
+func TestResponseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Header http.Header
+		Delay  time.Duration
+		OK     bool
+	}{
+		{
+			Header: http.Header{},
+		},
+		{
+			Header: http.Header{"Retry-After": {"120"}},
+			Delay:  2 * time.Minute,
+			OK:     true,
+		},
+		{
+			Header: http.Header{"Retry-After": {"-1"}},
+		},
+		{
+			Header: http.Header{"Retry-After": {"soon"}},
+		},
+		{
+			Header: http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:30 GMT"}},
+			Delay:  30 * time.Second,
+			OK:     true,
+		},
+		{
+			Header: http.Header{
+				"Retry-After": {"Wed, 21 Oct 2015 07:28:30 GMT"},
+				"Date":        {"Wed, 21 Oct 2015 07:28:20 GMT"},
+			},
+			Delay: 10 * time.Second,
+			OK:    true,
+		},
+		{
+			Header: http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:27:00 GMT"}},
+			Delay:  0,
+			OK:     true,
+		},
+	}
+	for _, tc := range testCases {
+		resp := &http.Response{Header: tc.Header}
+		d, ok := ResponseRetryAfter(resp, now)
+		if d != tc.Delay || ok != tc.OK {
+			t.Errorf("%v: expected (%v, %v), got (%v, %v)", tc.Header, tc.Delay, tc.OK, d, ok)
+		}
+	}
+}
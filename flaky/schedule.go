@@ -109,9 +109,12 @@ func (t sleepSchedule) Next(now time.Time) time.Time {
 // specified schedule. It allows executing operations that may only succeed
 // at the given time.
 type ScheduleExecutor struct {
-	clock *clock.Clock
-	sched Schedule
-	exec  Executor
+	clock    *clock.Clock
+	sched    Schedule
+	exec     Executor
+	observer Observer
+	store    ScheduleStore
+	policy   MissedPolicy
 }
 
 // WithSchedule restricts the executor to wait for the given scheduled time
@@ -129,38 +132,111 @@ func (s *ScheduleExecutor) WithClock(c *clock.Clock) *ScheduleExecutor {
 	if c == nil {
 		c = clock.System()
 	}
-	return &ScheduleExecutor{
-		clock: c,
-		sched: s.sched,
-		exec:  s.exec,
-	}
+	sc := *s
+	sc.clock = c
+	return &sc
+}
+
+// WithObserver returns a copy of the executor that notifies the given observer
+// about scheduled waits, executions of the underlying executor and give-ups.
+// Passing nil disables observation.
+func (s *ScheduleExecutor) WithObserver(o Observer) *ScheduleExecutor {
+	sc := *s
+	sc.observer = o
+	return &sc
+}
+
+// WithStore returns a copy of the executor that persists the time of the last
+// successful run in the given store. The executor computes the next scheduled
+// time relative to the last run and handles runs missed since then according
+// to the policy. Passing nil store disables persistence.
+//
+// It allows periodic jobs, e.g. executed using Watchdog, to avoid running twice
+// or skipping a run after a process restart.
+func (s *ScheduleExecutor) WithStore(store ScheduleStore, p MissedPolicy) *ScheduleExecutor {
+	sc := *s
+	sc.store = store
+	sc.policy = p
+	return &sc
 }
 
 // Execute implements the Executor interface.
 func (s *ScheduleExecutor) Execute(ctx context.Context, f Op) error {
 	now := s.clock.Now()
-	next := s.sched.Next(now)
+	next, err := s.next(ctx, now)
+	if err != nil {
+		return observeGiveUp(ctx, s.observer, err)
+	}
 
 	if now.Equal(next) {
-		return s.exec.Execute(ctx, f)
+		return s.execute(ctx, f)
 	}
 
 	if next.Before(now) {
-		return ErrNoNextSchedule
+		return observeGiveUp(ctx, s.observer, ErrNoNextSchedule)
 	}
 
 	d := next.Sub(now)
 	if !withinDeadline(ctx, d) {
-		return ErrScheduleDeadline
+		return observeGiveUp(ctx, s.observer, ErrScheduleDeadline)
 	}
 
+	observeWait(ctx, s.observer, d)
 	timer := s.clock.Timer(d)
 	defer timer.Stop()
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return observeGiveUp(ctx, s.observer, ctx.Err())
 	case <-timer.C():
 	}
 
-	return s.exec.Execute(ctx, f)
+	return s.execute(ctx, f)
+}
+
+// next returns the next scheduled time relative to now.
+func (s *ScheduleExecutor) next(ctx context.Context, now time.Time) (time.Time, error) {
+	if s.store == nil {
+		return s.sched.Next(now), nil
+	}
+
+	last, err := s.store.LastRun(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if last.IsZero() {
+		return s.sched.Next(now), nil
+	}
+
+	// Use the first scheduled time strictly after the last run.
+	after := last.Add(time.Nanosecond)
+	next := s.sched.Next(after)
+	if next.IsZero() || next.Before(after) {
+		return next, nil
+	}
+	if !next.Before(now) {
+		return next, nil
+	}
+
+	switch s.policy {
+	case CatchUpMissed:
+		return now, nil
+	default:
+		return s.sched.Next(now), nil
+	}
+}
+
+// execute executes an operation using the underlying executor.
+func (s *ScheduleExecutor) execute(ctx context.Context, f Op) error {
+	var start time.Time
+	if s.store != nil {
+		start = s.clock.Now()
+	}
+
+	err := observeAttempt(ctx, s.observer, s.clock, 0, func(ctx context.Context) error {
+		return s.exec.Execute(ctx, f)
+	})
+	if err == nil && s.store != nil {
+		err = s.store.SetLastRun(ctx, start)
+	}
+	return observeGiveUp(ctx, s.observer, err)
 }
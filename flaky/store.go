@@ -0,0 +1,121 @@
+package flaky
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ScheduleStore stores the time of the last successful run of a scheduled
+// operation. It allows ScheduleExecutor to survive process restarts without
+// running an operation twice or skipping it.
+type ScheduleStore interface {
+	// LastRun returns the time of the last successful run. It returns zero
+	// time if the operation has never run.
+	LastRun(ctx context.Context) (time.Time, error)
+	// SetLastRun sets the time of the last successful run.
+	SetLastRun(ctx context.Context, t time.Time) error
+}
+
+// MissedPolicy defines how ScheduleExecutor handles scheduled runs that were
+// missed since the last run, e.g. while the process was not running.
+type MissedPolicy uint8
+
+const (
+	// SkipMissed skips missed runs and waits for the next scheduled time
+	// after the current time.
+	SkipMissed MissedPolicy = iota
+	// CatchUpMissed executes an operation immediately if at least one
+	// scheduled run was missed. Multiple missed runs are coalesced into one.
+	CatchUpMissed
+)
+
+// String implements the fmt.Stringer interface.
+func (p MissedPolicy) String() string {
+	switch p {
+	case SkipMissed:
+		return "skip"
+	case CatchUpMissed:
+		return "catch-up"
+	default:
+		return "MissedPolicy(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// MemoryStore is a ScheduleStore that keeps the last run time in memory. It is
+// safe for concurrent use. The zero MemoryStore is ready for use.
+type MemoryStore struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// LastRun implements the ScheduleStore interface.
+func (m *MemoryStore) LastRun(_ context.Context) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.t, nil
+}
+
+// SetLastRun implements the ScheduleStore interface.
+func (m *MemoryStore) SetLastRun(_ context.Context, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.t = t
+	return nil
+}
+
+// FileStore is a ScheduleStore that keeps the last run time in a file. The file
+// contains the time in RFC 3339 format and is replaced atomically on update.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a new FileStore for the file at the given path. The
+// directory containing the file must exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path}
+}
+
+// LastRun implements the ScheduleStore interface. It returns zero time if the
+// file does not exist.
+func (f *FileStore) LastRun(_ context.Context) (time.Time, error) {
+	buf, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, string(bytes.TrimSpace(buf)))
+}
+
+// SetLastRun implements the ScheduleStore interface.
+func (f *FileStore) SetLastRun(_ context.Context, t time.Time) error {
+	dir, name := filepath.Dir(f.path), filepath.Base(f.path)
+	tmp, err := os.CreateTemp(dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	buf := t.AppendFormat(nil, time.RFC3339Nano)
+	buf = append(buf, '\n')
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,141 @@
+package flaky
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "last-run"))
+
+	last, err := store.LastRun(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !last.IsZero() {
+		t.Fatalf("expected zero time, got %v", last)
+	}
+
+	now := time.Date(2038, time.January, 19, 3, 14, 7, 42, time.UTC)
+	if err := store.SetLastRun(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last, err = store.LastRun(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !last.Equal(now) {
+		t.Fatalf("expected %v, got %v", now, last)
+	}
+}
+
+func TestFileStoreRelativePath(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// Temporary file must be created next to the file for atomic rename.
+	t.Setenv("TMPDIR", filepath.Join(dir, "missing"))
+
+	store := NewFileStore("last-run")
+
+	now := time.Date(2038, time.January, 19, 3, 14, 7, 42, time.UTC)
+	if err := store.SetLastRun(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last, err := store.LastRun(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !last.Equal(now) {
+		t.Fatalf("expected %v, got %v", now, last)
+	}
+}
+
+func TestScheduleExecutorStore(t *testing.T) {
+	date := func(hour, minute int) time.Time {
+		return time.Date(2038, time.January, 19, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		Name    string
+		Policy  MissedPolicy
+		LastRun time.Time
+		Wait    time.Duration
+		Stored  time.Time
+	}{
+		{
+			Name:    "Skip",
+			Policy:  SkipMissed,
+			LastRun: date(1, 0),
+			Wait:    30 * time.Minute,
+			Stored:  date(4, 0),
+		},
+		{
+			Name:    "CatchUp",
+			Policy:  CatchUpMissed,
+			LastRun: date(1, 0),
+			Stored:  date(3, 30),
+		},
+		{
+			Name:    "NotMissed",
+			Policy:  CatchUpMissed,
+			LastRun: date(3, 0),
+			Wait:    30 * time.Minute,
+			Stored:  date(4, 0),
+		},
+		{
+			Name:   "NeverRun",
+			Policy: CatchUpMissed,
+			Wait:   30 * time.Minute,
+			Stored: date(4, 0),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store := &MemoryStore{}
+			_ = store.SetLastRun(ctx, tc.LastRun)
+
+			fakeClock := fakeclock.Time(date(3, 30))
+			observeClock := observeclock.New(fakeClock)
+			if tc.Wait > 0 {
+				observer := observeClock.ObserveKind(observeclock.KindTimer, tc.Wait)
+				go func() {
+					<-observer
+					fakeClock.Add(tc.Wait)
+				}()
+			}
+
+			executor := WithSchedule(Once(), Every(time.Hour, nil)).
+				WithClock(clock.NewClock(observeClock)).
+				WithStore(store, tc.Policy)
+
+			var n int
+			err := executor.Execute(ctx, func(_ context.Context) error {
+				n++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != 1 {
+				t.Fatalf("expected n==1, got %d", n)
+			}
+
+			last, _ := store.LastRun(ctx)
+			if !last.Equal(tc.Stored) {
+				t.Fatalf("expected last run %v, got %v", tc.Stored, last)
+			}
+		})
+	}
+}
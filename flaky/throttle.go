@@ -0,0 +1,123 @@
+package flaky
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.pact.im/x/clock"
+)
+
+// ErrThrottled is an error that ThrottleExecutor returns when an Execute call
+// is suppressed within the throttle window or superseded by another trailing
+// call.
+var ErrThrottled = errors.New("flaky: throttled")
+
+// ThrottleExecutor is an executor that throttles an operation. That is, it
+// performs an operation immediately and then suppresses calls for the
+// specified window duration. It complements DebounceExecutor that performs an
+// operation only after calls stop for the given duration.
+//
+// Optionally, ThrottleExecutor performs a single trailing call at the end of
+// the window. In that case, calls within the window are coalesced and the last
+// one wins.
+type ThrottleExecutor struct {
+	clock    *clock.Clock
+	window   time.Duration
+	trailing bool
+
+	mu      sync.Mutex
+	until   time.Time
+	pending chan struct{}
+}
+
+// Throttle returns a new ThrottleExecutor instance for the given window
+// duration.
+func Throttle(window time.Duration) *ThrottleExecutor {
+	return &ThrottleExecutor{
+		clock:  clock.System(),
+		window: window,
+	}
+}
+
+// WithClock returns a copy of the executor that uses the given clock. The
+// returned executor does not share the state with the original one.
+func (t *ThrottleExecutor) WithClock(c *clock.Clock) *ThrottleExecutor {
+	if c == nil {
+		c = clock.System()
+	}
+	return &ThrottleExecutor{
+		clock:    c,
+		window:   t.window,
+		trailing: t.trailing,
+	}
+}
+
+// WithTrailing returns a copy of the executor that performs the last call
+// within the throttle window once the window ends. The returned executor does
+// not share the state with the original one.
+func (t *ThrottleExecutor) WithTrailing(trailing bool) *ThrottleExecutor {
+	return &ThrottleExecutor{
+		clock:    t.clock,
+		window:   t.window,
+		trailing: trailing,
+	}
+}
+
+// Execute calls the function f immediately unless another call has started
+// within the throttle window. Otherwise it returns ErrThrottled, or, if the
+// executor performs trailing calls, waits until the end of the window and then
+// calls f. A waiting call returns ErrThrottled if it is superseded by another
+// Execute call. Context expiration cancels a waiting call.
+//
+// Note that the window starts when the operation starts, so a long operation
+// may run concurrently with the next one.
+func (t *ThrottleExecutor) Execute(ctx context.Context, f Op) error {
+	t.mu.Lock()
+	now := t.clock.Now()
+	if t.pending == nil && !now.Before(t.until) {
+		t.until = now.Add(t.window)
+		t.mu.Unlock()
+		return unwrapInternal(f(ctx))
+	}
+	if !t.trailing {
+		t.mu.Unlock()
+		return ErrThrottled
+	}
+	if t.pending != nil {
+		close(t.pending)
+	}
+	superseded := make(chan struct{})
+	t.pending = superseded
+	d := t.until.Sub(now)
+	t.mu.Unlock()
+
+	if d > 0 {
+		timer := t.clock.Timer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			t.mu.Lock()
+			if t.pending == superseded {
+				t.pending = nil
+			}
+			t.mu.Unlock()
+			return ctx.Err()
+		case <-superseded:
+			return ErrThrottled
+		case <-timer.C():
+		}
+	}
+
+	t.mu.Lock()
+	if t.pending != superseded {
+		t.mu.Unlock()
+		return ErrThrottled
+	}
+	t.pending = nil
+	t.until = t.clock.Now().Add(t.window)
+	t.mu.Unlock()
+
+	return unwrapInternal(f(ctx))
+}
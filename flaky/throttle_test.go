@@ -0,0 +1,86 @@
+package flaky
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.pact.im/x/clock"
+	"go.pact.im/x/clock/fakeclock"
+	"go.pact.im/x/clock/observeclock"
+)
+
+func TestThrottleExecutor(t *testing.T) {
+	const window = time.Second
+
+	ctx := context.Background()
+
+	var n int
+	op := func(_ context.Context) error {
+		n++
+		return nil
+	}
+
+	fakeClock := fakeclock.Unix()
+	throttle := Throttle(window).WithClock(clock.NewClock(fakeClock))
+
+	if err := throttle.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := throttle.Execute(ctx, op); err != ErrThrottled {
+		t.Fatalf("expected %v, got %v", ErrThrottled, err)
+	}
+	fakeClock.Add(window)
+	if err := throttle.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+}
+
+func TestThrottleExecutorTrailing(t *testing.T) {
+	const window = time.Second
+
+	ctx := context.Background()
+
+	var n int
+	op := func(_ context.Context) error {
+		n++
+		return nil
+	}
+
+	fakeClock := fakeclock.Unix()
+	observeClock := observeclock.New(fakeClock)
+	throttle := Throttle(window).
+		WithClock(clock.NewClock(observeClock)).
+		WithTrailing(true)
+
+	if err := throttle.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	observer := observeClock.ObserveKind(observeclock.KindTimer, window)
+	superseded := make(chan error, 1)
+	go func() {
+		superseded <- throttle.Execute(ctx, func(_ context.Context) error {
+			panic("superseded operation should not be executed")
+		})
+	}()
+	<-observer
+
+	observer = observeClock.ObserveKind(observeclock.KindTimer, window)
+	go func() {
+		<-observer
+		fakeClock.Add(window)
+	}()
+	if err := throttle.Execute(ctx, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-superseded; err != ErrThrottled {
+		t.Fatalf("expected %v, got %v", ErrThrottled, err)
+	}
+	if n != 2 {
+		t.Fatalf("expected n==2, got %d", n)
+	}
+}
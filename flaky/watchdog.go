@@ -40,6 +40,23 @@ func (w *WatchdogExecutor) WithClock(c *clock.Clock) *WatchdogExecutor {
 	}
 }
 
+// WithObserver returns a copy of the executor that notifies the given observer
+// about scheduled waits, executions of the underlying executor and give-ups.
+// Passing nil disables observation.
+func (w *WatchdogExecutor) WithObserver(o Observer) *WatchdogExecutor {
+	return &WatchdogExecutor{
+		exec: w.exec.WithObserver(o),
+	}
+}
+
+// WithStore returns a copy of the executor that persists the time of the last
+// successful run in the given store. See ScheduleExecutor’s WithStore method.
+func (w *WatchdogExecutor) WithStore(store ScheduleStore, p MissedPolicy) *WatchdogExecutor {
+	return &WatchdogExecutor{
+		exec: w.exec.WithStore(store, p),
+	}
+}
+
 // Execute implements the Executor interface.
 func (w *WatchdogExecutor) Execute(ctx context.Context, f Op) error {
 	for {
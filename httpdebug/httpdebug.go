@@ -3,24 +3,46 @@ package httpdebug
 
 import (
 	"expvar"
+	"html"
 	"io"
 	"net/http"
 	"net/http/pprof"
 	"runtime"
 	"runtime/debug"
 	"strconv"
+	"strings"
 
 	"golang.org/x/net/trace"
 )
 
-// New returns a new HTTP handler for debug endpoints.
-func New() http.Handler {
+// Page is an additional debug page for the handler returned from [New].
+type Page struct {
+	// Path is the path of the page, e.g. “/debug/process”.
+	Path string
+	// Title is the title of the link on the index page.
+	Title string
+	// Handler serves GET requests for the page.
+	Handler http.Handler
+}
+
+// New returns a new HTTP handler for debug endpoints. Additional pages are
+// served at their paths and linked from the index page. For example, to serve
+// the process tree status:
+//
+//	tree := process.NewTree(runner)
+//	handler := httpdebug.New(httpdebug.Page{
+//		Path:    "/debug/process",
+//		Title:   "process tree",
+//		Handler: tree.Handler(),
+//	})
+func New(pages ...Page) http.Handler {
 	mux := http.NewServeMux()
 	handlePprof(mux)
 	handleExpvar(mux)
 	handleNetTrace(mux, true)
 	handleBuildInfo(mux)
-	handleIndex(mux)
+	handlePages(mux, pages)
+	handleIndex(mux, pages)
 	return mux
 }
 
@@ -67,18 +89,41 @@ func serveBuildInfo(w http.ResponseWriter, _ *http.Request) {
 	_, _ = io.WriteString(w, modinfo)
 }
 
-func handleIndex(mux *http.ServeMux) {
-	mux.HandleFunc("/debug/", serveIndex)
+func handlePages(mux *http.ServeMux, pages []Page) {
+	for _, p := range pages {
+		mux.Handle("GET "+p.Path, p.Handler)
+	}
+}
+
+func handleIndex(mux *http.ServeMux, pages []Page) {
+	page := debugPage(pages)
+	mux.HandleFunc("/debug/", func(w http.ResponseWriter, _ *http.Request) {
+		serveIndex(w, page)
+	})
 }
 
-func serveIndex(w http.ResponseWriter, _ *http.Request) {
+func serveIndex(w http.ResponseWriter, page string) {
 	h := w.Header()
 	h.Set("Content-Type", "text/html; charset=utf-8")
-	h.Set("Content-Length", strconv.Itoa(len(debugPage)))
-	_, _ = io.WriteString(w, debugPage)
+	h.Set("Content-Length", strconv.Itoa(len(page)))
+	_, _ = io.WriteString(w, page)
 }
 
-const debugPage = `<!doctype html>
+func debugPage(pages []Page) string {
+	var sb strings.Builder
+	sb.WriteString(debugPageHeader)
+	for _, p := range pages {
+		sb.WriteString(" <li><a href=\"")
+		sb.WriteString(html.EscapeString(p.Path))
+		sb.WriteString("\">")
+		sb.WriteString(html.EscapeString(p.Title))
+		sb.WriteString("</a></li>\n")
+	}
+	sb.WriteString(debugPageFooter)
+	return sb.String()
+}
+
+const debugPageHeader = `<!doctype html>
 <html lang=en>
 <title>Debug</title>
 <meta name=viewport content="width=device-width">
@@ -88,5 +133,7 @@ const debugPage = `<!doctype html>
  <li><a href=/debug/events>events</a></li>
  <li><a href=/debug/requests>traces</a></li>
  <li><a href=/debug/buildinfo>build info</a></li>
-</ul>
+`
+
+const debugPageFooter = `</ul>
 `
@@ -0,0 +1,38 @@
+package httpdebug
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewPages(t *testing.T) {
+	handler := New(Page{
+		Path:  "/debug/a&b",
+		Title: "process <tree>",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "page")
+		}),
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/a&b", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := rec.Body.String(); body != "page" {
+		t.Fatalf("expected %q, got %q", "page", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	const link = `<li><a href="/debug/a&amp;b">process &lt;tree&gt;</a></li>`
+	if body := rec.Body.String(); !strings.Contains(body, link) {
+		t.Fatalf("expected %q in index page, got %q", link, body)
+	}
+}
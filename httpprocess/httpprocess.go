@@ -7,25 +7,35 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 
 	"go.pact.im/x/httptrack"
 	"go.pact.im/x/process"
 )
 
+// ErrNotServing is an error that is returned from health check if the server
+// is not serving requests.
+var ErrNotServing = errors.New("httpprocess: server is not serving")
+
 // Server returns a [process.Runner] instance for the given HTTP server and
-// network listener.
+// network listener. The returned [process.Runner] implements the
+// [process.HealthChecker] interface that reports whether the server accepts
+// new connections.
 func Server(srv *http.Server, lis net.Listener) process.Runner {
 	var connTracker httptrack.ConnTracker
 	oldConnState := httptrack.Wrap(srv, &connTracker)
 	wrappedListener := &nilCloserListener{Listener: lis}
-	return process.Leaf(
+	r := &serverRunner{}
+	r.Runner = process.Leaf(
 		func(ctx context.Context) error {
 			oldBaseContext := srv.BaseContext
 			srv.BaseContext = func(_ net.Listener) context.Context {
 				return ctx
 			}
 
+			r.serving.Store(true)
 			err := srv.Serve(wrappedListener)
+			r.serving.Store(false)
 			if errors.Is(err, http.ErrServerClosed) {
 				err = nil
 			}
@@ -51,6 +61,8 @@ func Server(srv *http.Server, lis net.Listener) process.Runner {
 			return err
 		},
 		func(ctx context.Context) error {
+			r.serving.Store(false)
+
 			// Shutdown and Close forward error from net.Listener.Close.
 			// We already capture this error via nilCloserListener.
 			_ = srv.Shutdown(ctx)
@@ -58,6 +70,22 @@ func Server(srv *http.Server, lis net.Listener) process.Runner {
 			return nil
 		},
 	)
+	return r
+}
+
+// serverRunner is a [process.Runner] for HTTP server that implements the
+// [process.HealthChecker] interface.
+type serverRunner struct {
+	process.Runner
+	serving atomic.Bool
+}
+
+// CheckHealth implements the [process.HealthChecker] interface.
+func (r *serverRunner) CheckHealth(_ context.Context) error {
+	if !r.serving.Load() {
+		return ErrNotServing
+	}
+	return nil
 }
 
 // nilCloserListener is a wrapper around a [net.Listener] that ensures the
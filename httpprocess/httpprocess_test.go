@@ -18,7 +18,16 @@ func TestServer(t *testing.T) {
 		lis := netchan.NewListener()
 		srv := &http.Server{}
 
-		p := process.NewProcess(ctx, Server(srv, lis))
+		runner := Server(srv, lis)
+		checker, ok := runner.(process.HealthChecker)
+		if !ok {
+			t.Fatal("expected health checker")
+		}
+		if err := checker.CheckHealth(ctx); err != ErrNotServing {
+			t.Fatalf("expected %v, got %v", ErrNotServing, err)
+		}
+
+		p := process.NewProcess(ctx, runner)
 		if err := p.Start(ctx); err != nil {
 			t.Fatalf("start server: %v", err)
 		}
@@ -34,9 +43,16 @@ func TestServer(t *testing.T) {
 			t.Fatalf("http get: %v", err)
 		}
 
+		if err := checker.CheckHealth(ctx); err != nil {
+			t.Fatalf("check health: %v", err)
+		}
+
 		if err := p.Stop(ctx); err != nil {
 			t.Fatalf("stop server: %v", err)
 		}
+		if err := checker.CheckHealth(ctx); err != ErrNotServing {
+			t.Fatalf("expected %v, got %v", ErrNotServing, err)
+		}
 	})
 }
 
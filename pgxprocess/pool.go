@@ -8,7 +8,10 @@ import (
 	"go.pact.im/x/process"
 )
 
-var _ database = (*Pool)(nil)
+var (
+	_ database              = (*Pool)(nil)
+	_ process.HealthChecker = (*Pool)(nil)
+)
 
 // Pool wraps [pgxpool.Pool] to delay pool setup to application runtime.
 type Pool struct {
@@ -34,3 +37,11 @@ func (p *Pool) Run(ctx context.Context, callback process.Callback) error {
 
 	return callbackError
 }
+
+// CheckHealth implements the [process.HealthChecker] interface. It pings the
+// database using a connection from the pool. Note that [Conn] does not
+// implement health checks since a pgx connection is not safe for concurrent
+// use.
+func (p *Pool) CheckHealth(ctx context.Context) error {
+	return p.Ping(ctx)
+}
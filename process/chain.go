@@ -20,12 +20,17 @@ type chainRunner struct {
 	deps []Runner
 }
 
+func (r *chainRunner) children() []childRunner {
+	return unnamedChildren(r.deps)
+}
+
 func (r *chainRunner) Run(ctx context.Context, callback Callback) error {
-	s := chainState{deps: r.deps}
+	s := chainState{owner: r, deps: r.deps}
 	return s.Run(ctx, callback)
 }
 
 type chainState struct {
+	owner *chainRunner
 	index int
 	deps  []Runner // len(deps) >= 2
 	main  Callback
@@ -43,7 +48,7 @@ func (r *chainState) Run(ctx context.Context, callback Callback) error {
 	}
 	i := r.index
 	r.index++
-	return r.deps[i].Run(ctx, callback)
+	return runChild(ctx, r.owner, i, r.deps[i], callback)
 }
 
 func (r *chainState) next(ctx context.Context) error {
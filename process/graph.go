@@ -0,0 +1,302 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrDuplicateNode is an error that is returned if the Graph contains
+	// multiple nodes with the same name.
+	ErrDuplicateNode = errors.New("process: duplicate node")
+
+	// ErrUnknownDependency is an error that is returned if a Graph node
+	// depends on a node that does not exist.
+	ErrUnknownDependency = errors.New("process: unknown dependency")
+
+	// ErrDependencyCycle is an error that is returned if the Graph contains
+	// a dependency cycle.
+	ErrDependencyCycle = errors.New("process: dependency cycle")
+)
+
+// Graph is a builder for a [Runner] that starts and stops named processes
+// according to their dependencies. The zero Graph is an empty graph ready for
+// use.
+//
+// Unlike [Chain], [Parallel] and [Sequential] that require shaping process
+// topology into nested trees, Graph allows each process to declare its
+// dependencies by name. For example:
+//
+//	var g process.Graph
+//	g.Add("db", db)
+//	g.Add("cache", cache, "db")
+//	g.Add("http", httpServer, "cache")
+//	g.Add("grpc", grpcServer, "cache")
+//	runner, err := g.Runner()
+type Graph struct {
+	nodes []graphNode
+}
+
+// graphNode is a named process in the Graph.
+type graphNode struct {
+	name   string
+	runner Runner
+	deps   []string
+}
+
+// Add adds a named process to the graph that depends on processes with the
+// given names. Dependencies do not have to be added before the dependent
+// process.
+func (g *Graph) Add(name string, runner Runner, deps ...string) {
+	g.nodes = append(g.nodes, graphNode{
+		name:   name,
+		runner: runner,
+		deps:   deps,
+	})
+}
+
+// Runner returns a [Runner] instance for the graph. It returns an error that
+// names the offending nodes if the graph contains duplicate nodes, unknown
+// dependencies or dependency cycles.
+//
+// The resulting [Runner] starts each process once all of its dependencies are
+// successfully started, starting independent processes in parallel, and calls
+// callback after all processes are started. If any process fails to start,
+// processes that have already started are gracefully stopped. If any process
+// fails before the main callback returns, the context passed to callback is
+// canceled and all processes are gracefully stopped (unless the parent context
+// has expired).
+//
+// On shutdown, each process is stopped after all processes that depend on it
+// are stopped. Run returns callback error if it is not nil, otherwise it returns
+// combined errors from processes prefixed with their names.
+func (g *Graph) Runner() (Runner, error) {
+	index := make(map[string]int, len(g.nodes))
+	for i, node := range g.nodes {
+		if _, ok := index[node.name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateNode, node.name)
+		}
+		index[node.name] = i
+	}
+	for _, node := range g.nodes {
+		for _, dep := range node.deps {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, node.name, dep)
+			}
+		}
+	}
+
+	// Sort nodes topologically using depth-first search.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make([]int, len(g.nodes))
+	order := make([]int, 0, len(g.nodes))
+	var stack []int
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch marks[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(stack, i)
+			names := make([]string, 0, len(stack)-start+1)
+			for _, j := range stack[start:] {
+				names = append(names, g.nodes[j].name)
+			}
+			names = append(names, g.nodes[i].name)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		}
+		marks[i] = visiting
+		stack = append(stack, i)
+		for _, dep := range g.nodes[i].deps {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		marks[i] = visited
+		order = append(order, i)
+		return nil
+	}
+	for i := range g.nodes {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	// Build nodes in topological order with dependency indices.
+	position := make([]int, len(g.nodes))
+	for pos, i := range order {
+		position[i] = pos
+	}
+	nodes := make([]graphRunnerNode, len(order))
+	for pos, i := range order {
+		node := g.nodes[i]
+		deps := make([]int, len(node.deps))
+		for j, dep := range node.deps {
+			deps[j] = position[index[dep]]
+		}
+		nodes[pos] = graphRunnerNode{
+			name:   node.name,
+			runner: node.runner,
+			deps:   deps,
+		}
+	}
+	for pos, node := range nodes {
+		for _, dep := range node.deps {
+			nodes[dep].dependents = append(nodes[dep].dependents, pos)
+		}
+	}
+	return &graphRunner{nodes}, nil
+}
+
+type graphRunner struct {
+	nodes []graphRunnerNode // in topological order
+}
+
+type graphRunnerNode struct {
+	name       string
+	runner     Runner
+	deps       []int
+	dependents []int
+}
+
+func (r *graphRunner) children() []childRunner {
+	children := make([]childRunner, len(r.nodes))
+	for i, node := range r.nodes {
+		children[i] = childRunner{node.name, node.runner}
+	}
+	return children
+}
+
+func (r *graphRunner) Run(ctx context.Context, callback Callback) error {
+	var once sync.Once
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// fgctx is passed to callback and cancel is used from child
+	// process below to cancel callback invocation after startup.
+	fgctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	child := func(ctx context.Context, callback Callback) error {
+		err := callback(ctx)
+
+		// Propagate process shutdown to main callback and wait
+		// for it to return before exiting.
+		cancel()
+		wg.Wait()
+
+		return err
+	}
+
+	n := len(r.nodes)
+	procs := make([]*Process, n)
+	for i, node := range r.nodes {
+		dep := RunnerFunc(func(ctx context.Context, callback Callback) error {
+			return runChild(ctx, r, i, node.runner, callback)
+		})
+		procs[i] = NewProcess(ctx, Chain(dep, RunnerFunc(child)))
+	}
+
+	startError := r.start(ctx, procs, func() {
+		// If startup failed, we do not invoke callback.
+		// Unblock callbacks for processes that have already
+		// started.
+		once.Do(wg.Done)
+	})
+
+	var callbackError error
+	if startError == nil {
+		callbackError = callback(fgctx)
+
+		// Main callback has returned, unblock callbacks for
+		// processes.
+		wg.Done()
+	}
+
+	stopError := r.stop(ctx, procs)
+
+	switch {
+	case callbackError != nil:
+		return callbackError
+	case startError != nil:
+		return startError
+	}
+	return stopError
+}
+
+// start starts processes once their dependencies are started. It calls fail
+// if any process fails to start and returns combined startup errors.
+func (r *graphRunner) start(ctx context.Context, procs []*Process, fail func()) error {
+	startCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := len(r.nodes)
+	started := make([]chan struct{}, n)
+	for i := range started {
+		started[i] = make(chan struct{})
+	}
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i, node := range r.nodes {
+		wg.Go(func() {
+			for _, dep := range node.deps {
+				select {
+				case <-startCtx.Done():
+					return
+				case <-started[dep]:
+				}
+			}
+			if err := procs[i].Start(startCtx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", node.name, err)
+				fail()
+				cancel()
+				return
+			}
+			close(started[i])
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// stop stops processes after their dependents are stopped and returns combined
+// errors from processes.
+func (r *graphRunner) stop(ctx context.Context, procs []*Process) error {
+	n := len(r.nodes)
+	stopped := make([]chan struct{}, n)
+	for i := range stopped {
+		stopped[i] = make(chan struct{})
+	}
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i, node := range r.nodes {
+		wg.Go(func() {
+			defer close(stopped[i])
+			for _, dep := range node.dependents {
+				<-stopped[dep]
+			}
+			// We get either ErrProcessInvalidState or p.Err
+			// from Stop so it is safe to ignore error here.
+			_ = procs[i].Stop(ctx)
+			if err := procs[i].Err(); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", node.name, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
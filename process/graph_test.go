@@ -0,0 +1,139 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	node := func(name string) Runner {
+		return RunnerFunc(func(ctx context.Context, callback Callback) error {
+			record("start " + name)
+			defer record("stop " + name)
+			return callback(ctx)
+		})
+	}
+
+	var g Graph
+	g.Add("http", node("http"), "cache")
+	g.Add("cache", node("cache"), "db")
+	g.Add("db", node("db"))
+
+	r, err := g.Runner()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = r.Run(context.Background(), func(_ context.Context) error {
+		record("main")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"start db",
+		"start cache",
+		"start http",
+		"main",
+		"stop http",
+		"stop cache",
+		"stop db",
+	}
+	if !slices.Equal(expected, events) {
+		t.Fatalf("expected %v, got %v", expected, events)
+	}
+}
+
+func TestGraphStartError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	var stopped bool
+	var g Graph
+	g.Add("db", RunnerFunc(func(ctx context.Context, callback Callback) error {
+		defer func() { stopped = true }()
+		return callback(ctx)
+	}))
+	g.Add("cache", RunnerFunc(func(_ context.Context, _ Callback) error {
+		return errFailed
+	}), "db")
+	g.Add("http", RunnerFunc(func(_ context.Context, _ Callback) error {
+		t.Fatal("unexpected start")
+		return nil
+	}), "cache")
+
+	r, err := g.Runner()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = r.Run(context.Background(), func(_ context.Context) error {
+		t.Fatal("unexpected callback")
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+	if !stopped {
+		t.Fatal("expected db to be stopped")
+	}
+}
+
+func TestGraphErrors(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Nodes    [][]string
+		Expected error
+		Message  string
+	}{
+		{
+			Name:     "Duplicate",
+			Nodes:    [][]string{{"a"}, {"a"}},
+			Expected: ErrDuplicateNode,
+			Message:  `process: duplicate node: "a"`,
+		},
+		{
+			Name:     "Unknown",
+			Nodes:    [][]string{{"a", "b"}},
+			Expected: ErrUnknownDependency,
+			Message:  `process: unknown dependency: "a" depends on "b"`,
+		},
+		{
+			Name:     "Self",
+			Nodes:    [][]string{{"a", "a"}},
+			Expected: ErrDependencyCycle,
+			Message:  "process: dependency cycle: a -> a",
+		},
+		{
+			Name:     "Cycle",
+			Nodes:    [][]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "b"}},
+			Expected: ErrDependencyCycle,
+			Message:  "process: dependency cycle: b -> c -> d -> b",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var g Graph
+			for _, node := range tc.Nodes {
+				g.Add(node[0], Nop(), node[1:]...)
+			}
+			_, err := g.Runner()
+			if !errors.Is(err, tc.Expected) {
+				t.Fatalf("expected %v, got %v", tc.Expected, err)
+			}
+			if err.Error() != tc.Message {
+				t.Fatalf("expected %q, got %q", tc.Message, err.Error())
+			}
+		})
+	}
+}
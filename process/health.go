@@ -0,0 +1,198 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrNotRunning is an error that is returned from health checks if the process
+// is not running.
+var ErrNotRunning = errors.New("process: not running")
+
+// HealthChecker is an optional interface that [Runner] implementations may
+// implement to report their readiness to [Health].
+type HealthChecker interface {
+	// CheckHealth returns a non-nil error if the process is running but is
+	// not ready for use, e.g. a database connection is lost.
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthStatus is a health status of a process or its component.
+type HealthStatus string
+
+const (
+	// HealthUp indicates that the process is healthy.
+	HealthUp HealthStatus = "up"
+	// HealthDown indicates that the process is unhealthy.
+	HealthDown HealthStatus = "down"
+)
+
+// HealthReport is a health report for a process and its components.
+type HealthReport struct {
+	// Name is the name of the component, if any.
+	Name string `json:"name,omitempty"`
+	// Status is the aggregate status of the component. Component is down
+	// if either its own health check fails or any of its components is
+	// down.
+	Status HealthStatus `json:"status"`
+	// Error is the health check error message, if any.
+	Error string `json:"error,omitempty"`
+	// Components is a list of reports for components.
+	Components []HealthReport `json:"components,omitempty"`
+}
+
+// childRunner is a possibly named Runner that is composed into another Runner.
+type childRunner struct {
+	name   string
+	runner Runner
+}
+
+// parentRunner is implemented by Runner implementations that compose other
+// runners, e.g. [Chain] and [Parallel]. It allows [Health] to walk the process
+// tree.
+type parentRunner interface {
+	children() []childRunner
+}
+
+// runnerChildren returns children of the runner that either composes other
+// runners in this package or implements [Parent] interface.
+func runnerChildren(r Runner) []childRunner {
+	switch p := r.(type) {
+	case parentRunner:
+		return p.children()
+	case Parent:
+		return unnamedChildren(p.Children())
+	}
+	return nil
+}
+
+// unnamedChildren returns children for the given runners without names.
+func unnamedChildren(runners []Runner) []childRunner {
+	children := make([]childRunner, len(runners))
+	for i, r := range runners {
+		children[i] = childRunner{runner: r}
+	}
+	return children
+}
+
+// Health is a [Runner] wrapper that aggregates health of the process tree. It
+// reports liveness and readiness of the process in Kubernetes style.
+//
+// The process is live unless it has been started and terminated. The process
+// is ready if it is running and every [Runner] in the tree that implements
+// [HealthChecker] interface reports no errors.
+//
+// The tree is walked through runners returned from [Chain], [Parallel],
+// [Sequential], [Graph], [PrefixedError], [Timeout] and [Recover] and runners
+// that implement [Parent] interface. Graph nodes, runners wrapped with names
+// and runners that implement [Namer] interface are reported as named
+// components.
+type Health struct {
+	runner Runner
+	state  atomic.Int32
+}
+
+// NewHealth returns a new [Health] instance for the given runner.
+func NewHealth(runner Runner) *Health {
+	return &Health{runner: runner}
+}
+
+func (h *Health) children() []childRunner {
+	return []childRunner{{runner: h.runner}}
+}
+
+// Run implements the [Runner] interface.
+func (h *Health) Run(ctx context.Context, callback Callback) error {
+	h.state.Store(int32(StateStarting))
+	defer h.state.Store(int32(StateStopped))
+	return runChild(ctx, h, 0, h.runner, func(ctx context.Context) error {
+		h.state.Store(int32(StateRunning))
+		return callback(ctx)
+	})
+}
+
+// Live returns a liveness report for the process.
+func (h *Health) Live() HealthReport {
+	if State(h.state.Load()) == StateStopped {
+		return HealthReport{
+			Status: HealthDown,
+			Error:  ErrNotRunning.Error(),
+		}
+	}
+	return HealthReport{Status: HealthUp}
+}
+
+// Ready returns a readiness report for the process and its components.
+func (h *Health) Ready(ctx context.Context) HealthReport {
+	report := checkHealth(ctx, "", h.runner)
+	if State(h.state.Load()) != StateRunning {
+		report.Status = HealthDown
+		report.Error = ErrNotRunning.Error()
+	}
+	return report
+}
+
+// LiveHandler returns an [http.Handler] that serves liveness report as JSON.
+// It responds with [http.StatusServiceUnavailable] status code if the process
+// is not live.
+func (h *Health) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		serveHealthReport(w, h.Live())
+	})
+}
+
+// ReadyHandler returns an [http.Handler] that serves readiness report as JSON.
+// It responds with [http.StatusServiceUnavailable] status code if the process
+// is not ready.
+func (h *Health) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveHealthReport(w, h.Ready(r.Context()))
+	})
+}
+
+// serveHealthReport writes the health report as JSON response.
+func serveHealthReport(w http.ResponseWriter, report HealthReport) {
+	code := http.StatusOK
+	if report.Status != HealthUp {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// checkHealth returns a health report for the runner tree. Unnamed runners
+// that do not implement HealthChecker are not reported and their components
+// are hoisted to the parent report.
+func checkHealth(ctx context.Context, name string, r Runner) HealthReport {
+	report := HealthReport{
+		Name:   name,
+		Status: HealthUp,
+	}
+	for _, c := range runnerChildren(r) {
+		if n, ok := c.runner.(Namer); ok && c.name == "" {
+			c.name = n.Name()
+		}
+		child := checkHealth(ctx, c.name, c.runner)
+		if child.Status != HealthUp {
+			report.Status = HealthDown
+		}
+		_, checker := c.runner.(HealthChecker)
+		if c.name == "" && !checker {
+			report.Components = append(report.Components, child.Components...)
+			continue
+		}
+		report.Components = append(report.Components, child)
+	}
+	if c, ok := r.(HealthChecker); ok {
+		if err := c.CheckHealth(ctx); err != nil {
+			report.Status = HealthDown
+			report.Error = err.Error()
+		}
+	}
+	return report
+}
@@ -0,0 +1,128 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type healthCheckerRunner struct {
+	Runner
+	err error
+}
+
+func (r *healthCheckerRunner) CheckHealth(_ context.Context) error {
+	return r.err
+}
+
+func TestHealth(t *testing.T) {
+	errUnhealthy := errors.New("unhealthy")
+
+	var g Graph
+	g.Add("db", &healthCheckerRunner{Runner: Nop()})
+	g.Add("cache", &healthCheckerRunner{Runner: Nop(), err: errUnhealthy}, "db")
+	graph, err := g.Runner()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h := NewHealth(Parallel(
+		graph,
+		PrefixedError("http", &healthCheckerRunner{Runner: Nop()}),
+	))
+
+	if report := h.Live(); report.Status != HealthUp {
+		t.Fatalf("expected live process, got %+v", report)
+	}
+	if report := h.Ready(context.Background()); report.Status != HealthDown || report.Error != ErrNotRunning.Error() {
+		t.Fatalf("expected process that is not ready, got %+v", report)
+	}
+
+	err = h.Run(context.Background(), func(ctx context.Context) error {
+		rec := httptest.NewRecorder()
+		h.ReadyHandler().ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, "/readyz", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+		}
+
+		var report HealthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Status != HealthDown || report.Error != "" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+
+		expected := []HealthReport{
+			{Name: "db", Status: HealthUp},
+			{Name: "cache", Status: HealthDown, Error: errUnhealthy.Error()},
+			{Name: "http", Status: HealthUp},
+		}
+		if len(report.Components) != len(expected) {
+			t.Fatalf("expected %d components, got %+v", len(expected), report.Components)
+		}
+		for i, c := range report.Components {
+			if c.Name != expected[i].Name || c.Status != expected[i].Status || c.Error != expected[i].Error {
+				t.Fatalf("expected %+v, got %+v", expected[i], c)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+type compositeRunner struct {
+	runners []Runner
+}
+
+func (r *compositeRunner) Run(ctx context.Context, callback Callback) error {
+	return Chain(r.runners...).Run(ctx, callback)
+}
+
+func (r *compositeRunner) Children() []Runner {
+	return r.runners
+}
+
+func TestHealthParent(t *testing.T) {
+	errUnhealthy := errors.New("unhealthy")
+
+	h := NewHealth(&compositeRunner{[]Runner{
+		&namedRunner{Nop(), "config"},
+		&healthCheckerRunner{Runner: Nop(), err: errUnhealthy},
+	}})
+
+	err := h.Run(context.Background(), func(ctx context.Context) error {
+		report := h.Ready(ctx)
+		if report.Status != HealthDown || report.Error != "" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+
+		expected := []HealthReport{
+			{Name: "config", Status: HealthUp},
+			{Status: HealthDown, Error: errUnhealthy.Error()},
+		}
+		if len(report.Components) != len(expected) {
+			t.Fatalf("expected %d components, got %+v", len(expected), report.Components)
+		}
+		for i, c := range report.Components {
+			if c.Name != expected[i].Name || c.Status != expected[i].Status || c.Error != expected[i].Error {
+				t.Fatalf("expected %+v, got %+v", expected[i], c)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Namer is an optional interface that [Runner] implementations may implement to
+// report their name to [Tree] and [Health].
+type Namer interface {
+	// Name returns the name of the runner.
+	Name() string
+}
+
+// Parent is an optional interface that [Runner] implementations may implement
+// to report runners they compose to [Tree] and [Health]. Note that [Tree]
+// tracks the state of children only for runners provided by this package.
+type Parent interface {
+	// Children returns the runners composed into this runner.
+	Children() []Runner
+}
+
+// NodeStatus is a status of the [Runner] in the process tree.
+type NodeStatus struct {
+	// Name is the name of the runner.
+	Name string
+	// State is the state of the runner. It is [StateInitial] if the runner
+	// has never run, [StateStarting] before the runner calls its callback,
+	// [StateRunning] after that and [StateStopped] once Run returns.
+	State State
+	// Stopping is true if the runner is running but its callback has
+	// returned, that is, the runner is shutting down.
+	Stopping bool
+	// Uptime is the duration since the runner called its callback. If the
+	// runner has stopped, it is the duration it was running.
+	Uptime time.Duration
+	// Err is the error returned from the last Run invocation.
+	Err error
+	// Children is a list of statuses for runners composed into this
+	// runner.
+	Children []NodeStatus
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s NodeStatus) MarshalJSON() ([]byte, error) {
+	type nodeStatus struct {
+		Name     string       `json:"name"`
+		State    string       `json:"state"`
+		Stopping bool         `json:"stopping,omitempty"`
+		Uptime   string       `json:"uptime,omitempty"`
+		Error    string       `json:"error,omitempty"`
+		Children []NodeStatus `json:"children,omitempty"`
+	}
+	v := nodeStatus{
+		Name:     s.Name,
+		State:    s.State.String(),
+		Stopping: s.Stopping,
+		Children: s.Children,
+	}
+	if s.Uptime > 0 {
+		v.Uptime = s.Uptime.String()
+	}
+	if s.Err != nil {
+		v.Error = s.Err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// WriteText writes the status tree as indented human-readable text to w.
+func (s NodeStatus) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	s.appendText(&sb, 0)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// appendText appends the status tree at the given depth to sb.
+func (s NodeStatus) appendText(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(s.Name)
+	sb.WriteString(" (")
+	sb.WriteString(s.State.String())
+	if s.Stopping {
+		sb.WriteString(", stopping")
+	}
+	if s.Uptime > 0 {
+		sb.WriteString(", up ")
+		sb.WriteString(s.Uptime.Round(time.Millisecond).String())
+	}
+	sb.WriteString(")")
+	if s.Err != nil {
+		sb.WriteString(": ")
+		sb.WriteString(s.Err.Error())
+	}
+	sb.WriteString("\n")
+	for _, c := range s.Children {
+		c.appendText(sb, depth+1)
+	}
+}
+
+// Tree is a [Runner] wrapper that tracks the state of each runner in the
+// process tree. It allows inspecting which runners are still running, e.g.
+// when shutdown hangs.
+//
+// The tree is walked through runners returned from functions in this package
+// and runners that implement [Parent] interface. Runner names are taken from
+// [Graph] node names, [PrefixedError], [Timeout] and [Recover] names or
+// [Namer] interface.
+type Tree struct {
+	runner Runner
+	root   *treeNode
+}
+
+// NewTree returns a new [Tree] instance for the given runner.
+func NewTree(runner Runner) *Tree {
+	return &Tree{
+		runner: runner,
+		root:   newTreeNode("", runner),
+	}
+}
+
+func (t *Tree) children() []childRunner {
+	return []childRunner{{runner: t.runner}}
+}
+
+// Run implements the [Runner] interface.
+func (t *Tree) Run(ctx context.Context, callback Callback) error {
+	return t.root.run(ctx, nil, t.runner, callback)
+}
+
+// Status returns the current status of the process tree.
+func (t *Tree) Status() NodeStatus {
+	return t.root.status(time.Now())
+}
+
+// Handler returns an [http.Handler] that serves the status of the process tree
+// as text or, if “format” query parameter is “json”, as JSON.
+func (t *Tree) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := t.Status()
+		h := w.Header()
+		h.Set("Cache-Control", "no-store")
+		if r.URL.Query().Get("format") == "json" {
+			h.Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(status)
+			return
+		}
+		h.Set("Content-Type", "text/plain; charset=utf-8")
+		_ = status.WriteText(w)
+	})
+}
+
+// treeNodeKey is a context key for the treeNode of the currently running
+// parent runner.
+type treeNodeKey struct{}
+
+// treeNode tracks the state of a runner in the Tree.
+type treeNode struct {
+	name     string
+	runner   Runner
+	children []*treeNode
+
+	mu       sync.Mutex
+	state    State
+	stopping bool
+	started  time.Time
+	stopped  time.Time
+	err      error
+}
+
+// newTreeNode returns a new treeNode for the given runner and its children.
+func newTreeNode(name string, r Runner) *treeNode {
+	if name == "" {
+		name = runnerName(r)
+	}
+	n := &treeNode{
+		name:   name,
+		runner: r,
+	}
+	for _, c := range runnerChildren(r) {
+		n.children = append(n.children, newTreeNode(c.name, c.runner))
+	}
+	return n
+}
+
+// runnerName returns the name for the runner that was not named by its parent.
+func runnerName(r Runner) string {
+	switch r := r.(type) {
+	case Namer:
+		return r.Name()
+	case *chainRunner:
+		return "chain"
+	case *groupRunner:
+		return r.kind
+	case *graphRunner:
+		return "graph"
+	case *leafRunner:
+		return "leaf"
+	case *startStopRunner:
+		return "startstop"
+	case *prefixedErrorRunner:
+		return "prefixed"
+	case *timeoutRunner:
+		return "timeout"
+	case *recoverRunner:
+		return "recover"
+	case *Health:
+		return "health"
+	case *SignalsRunner:
+		return "signals"
+	case *NotifyRunner:
+		return "notify"
+	case *Tree:
+		return "tree"
+	case *nopRunner:
+		return "nop"
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// runChild runs the i-th child of the owner runner. If the owner is running in
+// a Tree, it tracks the state of the child.
+func runChild(ctx context.Context, owner Runner, i int, child Runner, callback Callback) error {
+	parent, _ := ctx.Value(treeNodeKey{}).(*treeNode)
+	if parent == nil || parent.runner != owner || i >= len(parent.children) {
+		return child.Run(ctx, callback)
+	}
+	return parent.children[i].run(ctx, parent, child, callback)
+}
+
+// run runs the runner and tracks its state. The parent is restored in the
+// context passed to callback.
+func (n *treeNode) run(ctx context.Context, parent *treeNode, r Runner, callback Callback) error {
+	n.mu.Lock()
+	n.state = StateStarting
+	n.stopping = false
+	n.started = time.Time{}
+	n.stopped = time.Time{}
+	n.mu.Unlock()
+
+	err := r.Run(context.WithValue(ctx, treeNodeKey{}, n), func(ctx context.Context) error {
+		n.mu.Lock()
+		n.state = StateRunning
+		n.started = time.Now()
+		n.mu.Unlock()
+
+		err := callback(context.WithValue(ctx, treeNodeKey{}, parent))
+
+		n.mu.Lock()
+		n.stopping = true
+		n.mu.Unlock()
+
+		return err
+	})
+
+	n.mu.Lock()
+	n.state = StateStopped
+	n.stopping = false
+	n.stopped = time.Now()
+	n.err = err
+	n.mu.Unlock()
+
+	return err
+}
+
+// status returns the status of the node and its children.
+func (n *treeNode) status(now time.Time) NodeStatus {
+	n.mu.Lock()
+	s := NodeStatus{
+		Name:     n.name,
+		State:    n.state,
+		Stopping: n.stopping,
+		Err:      n.err,
+	}
+	if !n.started.IsZero() {
+		end := now
+		if !n.stopped.IsZero() {
+			end = n.stopped
+		}
+		s.Uptime = end.Sub(n.started)
+	}
+	n.mu.Unlock()
+
+	if len(n.children) != 0 {
+		s.Children = make([]NodeStatus, len(n.children))
+		for i, c := range n.children {
+			s.Children[i] = c.status(now)
+		}
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type namedRunner struct {
+	Runner
+	name string
+}
+
+func (r *namedRunner) Name() string {
+	return r.name
+}
+
+func TestTree(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	var g Graph
+	g.Add("db", Nop())
+	g.Add("cache", RunnerFunc(func(ctx context.Context, callback Callback) error {
+		_ = callback(ctx)
+		return errFailed
+	}), "db")
+	graph, err := g.Runner()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tree := NewTree(Chain(
+		&namedRunner{Nop(), "config"},
+		Parallel(graph, PrefixedError("http", Nop())),
+	))
+
+	if s := tree.Status(); s.State != StateInitial {
+		t.Fatalf("expected %v state, got %v", StateInitial, s.State)
+	}
+
+	err = tree.Run(context.Background(), func(_ context.Context) error {
+		var sb strings.Builder
+		if err := tree.Status().WriteText(&sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text := sb.String()
+		for _, line := range []string{
+			"chain (running, up ",
+			"\n  config (running, up ",
+			"\n  parallel (running, up ",
+			"\n    graph (running, up ",
+			"\n      db (running, up ",
+			"\n      cache (running, up ",
+			"\n    prefixed (running, up ",
+			"\n      http (running, up ",
+		} {
+			if !strings.Contains(text, line) {
+				t.Fatalf("expected %q in status text, got:\n%s", line, text)
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+
+	rec := httptest.NewRecorder()
+	tree.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+
+	var status struct {
+		Name     string `json:"name"`
+		State    string `json:"state"`
+		Error    string `json:"error"`
+		Children []struct {
+			Name  string `json:"name"`
+			State string `json:"state"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Name != "chain" || status.State != "stopped" || status.Error == "" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if len(status.Children) != 2 || status.Children[0].Name != "config" || status.Children[0].State != "stopped" {
+		t.Fatalf("unexpected children: %+v", status.Children)
+	}
+}
+
+func TestTreeStopping(t *testing.T) {
+	stopping := make(chan struct{})
+	release := make(chan struct{})
+
+	tree := NewTree(Chain(
+		PrefixedError("stuck", RunnerFunc(func(ctx context.Context, callback Callback) error {
+			err := callback(ctx)
+			close(stopping)
+			<-release
+			return err
+		})),
+		Nop(),
+	))
+
+	done := make(chan error)
+	go func() {
+		done <- tree.Run(context.Background(), func(_ context.Context) error {
+			return nil
+		})
+	}()
+
+	<-stopping
+	stuck := tree.Status().Children[0].Children[0]
+	if stuck.Name != "stuck" || stuck.State != StateRunning || !stuck.Stopping {
+		t.Fatalf("unexpected status: %+v", stuck)
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// listenFDsStart is the first file descriptor passed with socket activation.
+const listenFDsStart = 3
+
+// unknownListenerName is the name of listeners without LISTEN_FDNAMES entry.
+const unknownListenerName = "unknown"
+
+// ErrListenerNotFile is an error that is returned from [InheritListeners] if
+// the listener does not support obtaining the underlying file.
+var ErrListenerNotFile = errors.New("process: listener does not have a file")
+
+// Listeners is a set of named listeners inherited from the parent process
+// using systemd socket activation protocol (see sd_listen_fds(3)). The zero
+// Listeners is an empty set ready for use. Listeners is safe for concurrent
+// use.
+type Listeners struct {
+	mu    sync.Mutex
+	files map[string][]*os.File
+}
+
+// InheritedListeners returns listeners passed to the current process via
+// LISTEN_FDS, LISTEN_FDNAMES and LISTEN_PID environment variables and unsets
+// these variables so that they are not passed to child processes. Listeners
+// without names are named “unknown”.
+//
+// Unlike sd_listen_fds(3), it also accepts listeners if LISTEN_PID is not set
+// to allow passing listeners on re-exec with [InheritListeners].
+func InheritedListeners() (*Listeners, error) {
+	count := os.Getenv("LISTEN_FDS")
+	pid := os.Getenv("LISTEN_PID")
+	names := os.Getenv("LISTEN_FDNAMES")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	if count == "" || pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return &Listeners{}, nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("process: invalid LISTEN_FDS value %q", count)
+	}
+
+	fds := make([]uintptr, n)
+	for i := range fds {
+		fds[i] = uintptr(listenFDsStart + i)
+	}
+	var fdNames []string
+	if names != "" {
+		fdNames = strings.Split(names, ":")
+	}
+	return newListeners(fds, fdNames), nil
+}
+
+// newListeners returns Listeners for the given file descriptors and names. It
+// sets close-on-exec flag for the file descriptors since inherited descriptors
+// may not have it set.
+func newListeners(fds []uintptr, names []string) *Listeners {
+	l := &Listeners{files: make(map[string][]*os.File, len(fds))}
+	for i, fd := range fds {
+		name := unknownListenerName
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		setCloseOnExec(fd)
+		f := os.NewFile(fd, name)
+		if f == nil {
+			continue
+		}
+		l.files[name] = append(l.files[name], f)
+	}
+	return l
+}
+
+// Listen returns the inherited listener with the given name. If there is no
+// such listener, it falls back to listening on the given network address. If
+// multiple listeners have the same name, they are returned in order.
+func (l *Listeners) Listen(ctx context.Context, name, network, address string) (net.Listener, error) {
+	if f := l.take(name); f != nil {
+		defer func() { _ = f.Close() }()
+		lis, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("process: inherited listener %q: %w", name, err)
+		}
+		return lis, nil
+	}
+	var lc net.ListenConfig
+	return lc.Listen(ctx, network, address)
+}
+
+// Close closes inherited listeners that were not returned from Listen.
+func (l *Listeners) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for _, files := range l.files {
+		for _, f := range files {
+			errs = append(errs, f.Close())
+		}
+	}
+	l.files = nil
+	return errors.Join(errs...)
+}
+
+// take removes and returns the first file with the given name or nil if there
+// is no such file.
+func (l *Listeners) take(name string) *os.File {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files := l.files[name]
+	if len(files) == 0 {
+		return nil
+	}
+	l.files[name] = files[1:]
+	return files[0]
+}
+
+// InheritListeners configures the command to inherit the given named listeners
+// using the protocol supported by [InheritedListeners], e.g. for zero-downtime
+// re-exec. It sets cmd.ExtraFiles to listener files ordered by name and sets
+// LISTEN_FDS and LISTEN_FDNAMES environment variables, so cmd.ExtraFiles must
+// be empty. Listeners must implement File method as [net.TCPListener] and
+// [net.UnixListener] do.
+//
+// The caller should close files in cmd.ExtraFiles after the command is started.
+func InheritListeners(cmd *exec.Cmd, listeners map[string]net.Listener) error {
+	if len(cmd.ExtraFiles) != 0 {
+		return errors.New("process: command already has extra files")
+	}
+
+	names := slices.Sorted(maps.Keys(listeners))
+
+	files := make([]*os.File, 0, len(names))
+	for _, name := range names {
+		lis, ok := listeners[name].(interface{ File() (*os.File, error) })
+		if !ok {
+			closeFiles(files)
+			return fmt.Errorf("%w: %q", ErrListenerNotFile, name)
+		}
+		f, err := lis.File()
+		if err != nil {
+			closeFiles(files)
+			return fmt.Errorf("process: listener %q: %w", name, err)
+		}
+		files = append(files, f)
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	env = slices.DeleteFunc(slices.Clone(env), func(kv string) bool {
+		return strings.HasPrefix(kv, "LISTEN_FDS=") ||
+			strings.HasPrefix(kv, "LISTEN_PID=") ||
+			strings.HasPrefix(kv, "LISTEN_FDNAMES=")
+	})
+	cmd.Env = append(env,
+		"LISTEN_FDS="+strconv.Itoa(len(files)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+	)
+	cmd.ExtraFiles = files
+	return nil
+}
+
+// closeFiles closes the given files ignoring errors.
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,107 @@
+//go:build unix
+
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestListeners(t *testing.T) {
+	ctx := context.Background()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = lis.Close() }()
+
+	f, err := lis.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fd, err := syscall.Dup(int(f.Fd()))
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listeners := newListeners([]uintptr{uintptr(fd)}, []string{"http"})
+	defer func() { _ = listeners.Close() }()
+
+	flags, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_GETFD, 0)
+	if errno != 0 {
+		t.Fatalf("unexpected error: %v", errno)
+	}
+	if flags&syscall.FD_CLOEXEC == 0 {
+		t.Fatal("expected close-on-exec flag")
+	}
+
+	inherited, err := listeners.Listen(ctx, "http", "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = inherited.Close() }()
+	if inherited.Addr().String() != lis.Addr().String() {
+		t.Fatalf("expected %v, got %v", lis.Addr(), inherited.Addr())
+	}
+
+	fallback, err := listeners.Listen(ctx, "http", "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = fallback.Close() }()
+	if fallback.Addr().String() == lis.Addr().String() {
+		t.Fatal("expected fallback listener")
+	}
+}
+
+func TestInheritedListeners(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "0")
+	t.Setenv("LISTEN_FDNAMES", "")
+	t.Setenv("LISTEN_PID", "1")
+
+	listeners, err := InheritedListeners()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(listeners.files) != 0 {
+		t.Fatalf("expected no listeners, got %v", listeners.files)
+	}
+	if _, ok := os.LookupEnv("LISTEN_FDS"); ok {
+		t.Fatal("expected LISTEN_FDS to be unset")
+	}
+
+	t.Setenv("LISTEN_FDS", "invalid")
+	if _, err := InheritedListeners(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInheritListeners(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = lis.Close() }()
+
+	cmd := exec.Command("true")
+	cmd.Env = []string{"LISTEN_FDS=2", "PATH=/bin"}
+	if err := InheritListeners(cmd, map[string]net.Listener{"http": lis}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closeFiles(cmd.ExtraFiles)
+
+	expected := []string{"PATH=/bin", "LISTEN_FDS=1", "LISTEN_FDNAMES=http"}
+	if !slices.Equal(expected, cmd.Env) {
+		t.Fatalf("expected %v, got %v", expected, cmd.Env)
+	}
+	if len(cmd.ExtraFiles) != 1 {
+		t.Fatalf("expected 1 extra file, got %d", len(cmd.ExtraFiles))
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package process
+
+import "syscall"
+
+// setCloseOnExec sets close-on-exec flag for the file descriptor so that it is
+// not leaked to child processes.
+func setCloseOnExec(fd uintptr) {
+	syscall.CloseOnExec(int(fd))
+}
@@ -0,0 +1,119 @@
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NotifyRunner is a [Runner] wrapper that implements systemd service readiness
+// notification protocol (see sd_notify(3)) for services with Type=notify.
+//
+// It sends READY=1 once the wrapped runner invokes its callback, STOPPING=1
+// when the callback returns and WATCHDOG=1 keep-alive messages at half of the
+// watchdog interval while the runner is running. Messages are sent to the
+// unixgram socket from NOTIFY_SOCKET environment variable. If the variable is
+// not set, the wrapped runner is run as is.
+type NotifyRunner struct {
+	runner   Runner
+	socket   string
+	watchdog time.Duration
+}
+
+// Notify returns a new [NotifyRunner] instance for the given runner. By
+// default, the watchdog interval is read from WATCHDOG_USEC and WATCHDOG_PID
+// environment variables.
+func Notify(runner Runner) *NotifyRunner {
+	return &NotifyRunner{runner: runner}
+}
+
+// WithSocket returns a copy of the runner that sends notifications to the
+// socket at the given path instead of NOTIFY_SOCKET environment variable. The
+// path starting with “@” refers to the Linux abstract namespace.
+func (r *NotifyRunner) WithSocket(path string) *NotifyRunner {
+	rc := *r
+	rc.socket = path
+	return &rc
+}
+
+// WithWatchdog returns a copy of the runner that uses the given watchdog
+// interval instead of WATCHDOG_USEC environment variable. Keep-alive messages
+// are sent at half of the interval. A negative interval disables keep-alive
+// messages.
+func (r *NotifyRunner) WithWatchdog(d time.Duration) *NotifyRunner {
+	rc := *r
+	rc.watchdog = d
+	return &rc
+}
+
+func (r *NotifyRunner) children() []childRunner {
+	return []childRunner{{runner: r.runner}}
+}
+
+// Run implements the [Runner] interface. It returns an error if the
+// notification socket cannot be opened. Errors from sending notifications are
+// ignored.
+func (r *NotifyRunner) Run(ctx context.Context, callback Callback) error {
+	path := r.socket
+	if path == "" {
+		path = os.Getenv("NOTIFY_SOCKET")
+	}
+	if path == "" {
+		return runChild(ctx, r, 0, r.runner, callback)
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	notify := func(state string) {
+		_, _ = conn.Write([]byte(state))
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(done)
+
+	return runChild(ctx, r, 0, r.runner, func(ctx context.Context) error {
+		notify("READY=1")
+		if d := r.watchdogInterval(); d > 0 {
+			wg.Go(func() {
+				ticker := time.NewTicker(d / 2)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						notify("WATCHDOG=1")
+					}
+				}
+			})
+		}
+		err := callback(ctx)
+		notify("STOPPING=1")
+		return err
+	})
+}
+
+// watchdogInterval returns the watchdog interval or zero if the watchdog is
+// disabled.
+func (r *NotifyRunner) watchdogInterval() time.Duration {
+	if r.watchdog != 0 {
+		return max(r.watchdog, 0)
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
@@ -0,0 +1,71 @@
+package process
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram socket is not supported: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	read := func() string {
+		buf := make([]byte, 64)
+		n, _, err := conn.ReadFromUnix(buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(buf[:n])
+	}
+
+	runner := Notify(Nop()).
+		WithSocket(path).
+		WithWatchdog(2 * time.Millisecond)
+
+	err = runner.Run(context.Background(), func(_ context.Context) error {
+		if msg := read(); msg != "READY=1" {
+			t.Fatalf("expected READY=1, got %q", msg)
+		}
+		if msg := read(); msg != "WATCHDOG=1" {
+			t.Fatalf("expected WATCHDOG=1, got %q", msg)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for {
+		msg := read()
+		if msg == "WATCHDOG=1" {
+			continue
+		}
+		if msg != "STOPPING=1" {
+			t.Fatalf("expected STOPPING=1, got %q", msg)
+		}
+		break
+	}
+}
+
+func TestNotifyNoSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	var called bool
+	err := Notify(Nop()).Run(context.Background(), func(_ context.Context) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected callback to be called")
+	}
+}
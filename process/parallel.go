@@ -27,6 +27,7 @@ func Parallel(deps ...Runner) Runner {
 		return deps[0]
 	}
 	return &groupRunner{
+		kind: "parallel",
 		deps: deps,
 		exec: task.ParallelExecutor(),
 	}
@@ -42,16 +43,29 @@ func Sequential(deps ...Runner) Runner {
 		return deps[0]
 	}
 	return &groupRunner{
+		kind: "sequential",
 		deps: deps,
 		exec: task.SequentialExecutor(),
 	}
 }
 
 type groupRunner struct {
+	kind string
 	deps []Runner
 	exec task.Executor
 }
 
+func (r *groupRunner) children() []childRunner {
+	return unnamedChildren(r.deps)
+}
+
+// child returns a Runner that runs the i-th dependency.
+func (r *groupRunner) child(i int, dep Runner) Runner {
+	return RunnerFunc(func(ctx context.Context, callback Callback) error {
+		return runChild(ctx, r, i, dep, callback)
+	})
+}
+
 func (r *groupRunner) Run(ctx context.Context, callback Callback) error {
 	var once sync.Once
 	var wg sync.WaitGroup
@@ -79,7 +93,7 @@ func (r *groupRunner) Run(ctx context.Context, callback Callback) error {
 	startTasks := tasksArena[0*n : 1*n]
 	stopTasks := tasksArena[1*n : 2*n]
 	for i, dep := range r.deps {
-		p := NewProcess(ctx, Chain(dep, RunnerFunc(child)))
+		p := NewProcess(ctx, Chain(r.child(i, dep), RunnerFunc(child)))
 		procs[i] = p
 		startTasks[i] = func(ctx context.Context) error {
 			err := p.Start(ctx)
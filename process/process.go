@@ -6,8 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // ErrProcessInvalidState is an error that is returned if the process
@@ -37,6 +40,46 @@ const (
 	StateStopped
 )
 
+// String implements the fmt.Stringer interface.
+func (s State) String() string {
+	switch s {
+	case StateInitial:
+		return "initial"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopped:
+		return "stopped"
+	default:
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Transition describes a transition between process states.
+type Transition struct {
+	// From is the state before the transition.
+	From State
+	// To is the state after the transition.
+	To State
+	// Time is the time of the transition.
+	Time time.Time
+	// Err is the terminal process error. It may be non-nil only on
+	// transition to StateStopped.
+	Err error
+}
+
+// transitionListener is a function registered with Process.OnTransition. It is
+// wrapped in a struct so that the pointer uniquely identifies a registration.
+type transitionListener struct {
+	f func(Transition)
+}
+
 // Process represents a stateful process that is running in the background.
 // It exposes Start and Stop methods that use an underlying state machine to
 // prevent operations in invalid states. Process is safe for concurrent use.
@@ -51,6 +94,9 @@ type Process struct {
 	stateMu sync.Mutex
 	state   State
 
+	listenersMu sync.Mutex
+	listeners   []*transitionListener
+
 	cancel context.CancelFunc
 
 	stop chan struct{}
@@ -87,6 +133,31 @@ func (p *Process) State() State {
 	return p.state
 }
 
+// OnTransition registers a function that is called on each process state
+// transition and returns a function that removes the registration. Listeners
+// are called synchronously from the goroutine that performs the transition
+// and must not block.
+//
+// Transition to [StateStopped] is reported when the process terminates with
+// the terminal error, that is, after Stop is called and the underlying
+// [Runner] returns. If Stop is called in the initial state, the transition is
+// reported immediately.
+func (p *Process) OnTransition(f func(Transition)) (remove func()) {
+	l := &transitionListener{f}
+
+	p.listenersMu.Lock()
+	p.listeners = append(p.listeners, l)
+	p.listenersMu.Unlock()
+
+	return func() {
+		p.listenersMu.Lock()
+		defer p.listenersMu.Unlock()
+		p.listeners = slices.DeleteFunc(p.listeners, func(other *transitionListener) bool {
+			return other == l
+		})
+	}
+}
+
 // Start starts the process or cancels the underlying process context on error.
 //
 // The startup deadline may be set using the given ctx context. Note that the
@@ -104,11 +175,16 @@ func (p *Process) Start(ctx context.Context) error {
 	}) {
 		return ErrProcessInvalidState
 	}
+	p.notify(StateInitial, StateStarting, nil)
 
 	init := make(chan struct{})
 	go func() {
+		from := StateStarting
 		err := p.runner.Run(bgctx, func(bgctx context.Context) error {
-			_ = p.transition(StateRunning, nil)
+			if p.transition(StateRunning, nil) {
+				from = StateRunning
+				p.notify(StateStarting, StateRunning, nil)
+			}
 
 			close(init)
 
@@ -125,6 +201,7 @@ func (p *Process) Start(ctx context.Context) error {
 		if err != nil {
 			p.err.Store(err)
 		}
+		p.notify(from, StateStopped, err)
 		close(p.done)
 	}()
 
@@ -176,6 +253,7 @@ func (p *Process) Stop(ctx context.Context) error {
 	}
 	if initial {
 		// We prevented a start. Do nothing.
+		p.notify(StateInitial, StateStopped, nil)
 		return nil
 	}
 	select {
@@ -192,6 +270,27 @@ func (p *Process) Stop(ctx context.Context) error {
 	}
 }
 
+// notify calls transition listeners.
+func (p *Process) notify(from, to State, err error) {
+	p.listenersMu.Lock()
+	listeners := slices.Clone(p.listeners)
+	p.listenersMu.Unlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+
+	t := Transition{
+		From: from,
+		To:   to,
+		Time: time.Now(),
+		Err:  err,
+	}
+	for _, l := range listeners {
+		l.f(t)
+	}
+}
+
 // transition advances to the next process state. It returns false if there is
 // not transition from the current to the given next state.
 func (p *Process) transition(next State, advance func()) bool {
@@ -0,0 +1,68 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProcessOnTransition(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	p := NewProcess(context.Background(), RunnerFunc(func(ctx context.Context, callback Callback) error {
+		_ = callback(ctx)
+		return errFailed
+	}))
+
+	var transitions []Transition
+	p.OnTransition(func(t Transition) {
+		transitions = append(transitions, t)
+	})
+	remove := p.OnTransition(func(_ Transition) {
+		t.Fatal("unexpected call")
+	})
+	remove()
+
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Stop(context.Background()); !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+
+	expected := []Transition{
+		{From: StateInitial, To: StateStarting},
+		{From: StateStarting, To: StateRunning},
+		{From: StateRunning, To: StateStopped, Err: errFailed},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %d transitions, got %d", len(expected), len(transitions))
+	}
+	for i, tr := range transitions {
+		if tr.From != expected[i].From || tr.To != expected[i].To || tr.Err != expected[i].Err {
+			t.Fatalf("expected %v -> %v (%v), got %v -> %v (%v)",
+				expected[i].From, expected[i].To, expected[i].Err,
+				tr.From, tr.To, tr.Err,
+			)
+		}
+		if tr.Time.IsZero() {
+			t.Fatal("expected non-zero transition time")
+		}
+	}
+}
+
+func TestProcessOnTransitionInitial(t *testing.T) {
+	p := NewProcess(context.Background(), Nop())
+
+	var transitions []Transition
+	p.OnTransition(func(t Transition) {
+		transitions = append(transitions, t)
+	})
+
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].From != StateInitial || transitions[0].To != StateStopped {
+		t.Fatalf("unexpected transitions: %v", transitions)
+	}
+}
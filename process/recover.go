@@ -0,0 +1,90 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is an error that is returned from runners created with [Recover]
+// if the runner panics.
+type PanicError struct {
+	// Name is the name of the runner.
+	Name string
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("process: %s: panic: %v", e.Name, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// callbackPanic wraps a panic value from the callback so that it is not
+// recovered by the runner that invoked the callback.
+type callbackPanic struct {
+	value any
+}
+
+// Recover returns a [Runner] instance that converts panics in the given named
+// runner into [PanicError] that is returned from Run. When used with
+// [Process], the error is available via Err method, so that supervisors can
+// restart the runner.
+//
+// Panics in the callback are propagated as is since they do not originate from
+// the runner. Note that panics in goroutines started by the runner cannot be
+// recovered.
+func Recover(name string, runner Runner) Runner {
+	return &recoverRunner{
+		name:   name,
+		runner: runner,
+	}
+}
+
+type recoverRunner struct {
+	name   string
+	runner Runner
+}
+
+func (r *recoverRunner) children() []childRunner {
+	return []childRunner{{r.name, r.runner}}
+}
+
+func (r *recoverRunner) Run(ctx context.Context, callback Callback) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if p, ok := v.(*callbackPanic); ok {
+			panic(p.value)
+		}
+		err = &PanicError{
+			Name:  r.name,
+			Value: v,
+			Stack: debug.Stack(),
+		}
+	}()
+	return runChild(ctx, r, 0, r.runner, func(ctx context.Context) error {
+		var ok bool
+		defer func() {
+			if ok {
+				return
+			}
+			if v := recover(); v != nil {
+				panic(&callbackPanic{v})
+			}
+		}()
+		err := callback(ctx)
+		ok = true
+		return err
+	})
+}
@@ -0,0 +1,62 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	ctx := context.Background()
+
+	p := NewProcess(ctx, Parallel(
+		Nop(),
+		Recover("worker", RunnerFunc(func(ctx context.Context, callback Callback) error {
+			_ = callback(ctx)
+			panic("oops")
+		})),
+	))
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = p.Stop(ctx)
+
+	var panicError *PanicError
+	if !errors.As(p.Err(), &panicError) {
+		t.Fatalf("expected panic error, got %v", p.Err())
+	}
+	if panicError.Name != "worker" || panicError.Value != "oops" {
+		t.Fatalf("unexpected panic error: %v", panicError)
+	}
+	if !bytes.Contains(panicError.Stack, []byte("TestRecover")) {
+		t.Fatalf("expected stack trace, got:\n%s", panicError.Stack)
+	}
+}
+
+func TestRecoverStartup(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	r := Recover("worker", RunnerFunc(func(_ context.Context, _ Callback) error {
+		panic(errFailed)
+	}))
+	err := r.Run(context.Background(), func(_ context.Context) error {
+		t.Fatal("unexpected callback")
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected %v, got %v", errFailed, err)
+	}
+}
+
+func TestRecoverCallback(t *testing.T) {
+	defer func() {
+		if v := recover(); v != "callback" {
+			t.Fatalf("expected callback panic, got %v", v)
+		}
+	}()
+	_ = Recover("nop", Nop()).Run(context.Background(), func(_ context.Context) error {
+		panic("callback")
+	})
+	t.Fatal("expected panic")
+}
@@ -69,9 +69,13 @@ func PrefixedError(prefix string, runner Runner) Runner {
 	}
 }
 
+func (p *prefixedErrorRunner) children() []childRunner {
+	return []childRunner{{p.prefix, p.runner}}
+}
+
 // Run implements the Runner interface.
 func (p *prefixedErrorRunner) Run(ctx context.Context, callback Callback) error {
-	err := p.runner.Run(ctx, callback)
+	err := runChild(ctx, p, 0, p.runner, callback)
 	if err == nil {
 		return nil
 	}
@@ -0,0 +1,146 @@
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SignalError is an error that is used as a cause of the context cancellation
+// when [SignalsRunner] receives a termination signal.
+type SignalError struct {
+	// Signal is the received signal.
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+func (e *SignalError) Error() string {
+	return "process: received signal " + e.Signal.String()
+}
+
+// SignalsRunner is a [Runner] that handles OS signals. It cancels the context
+// passed to callback on the first SIGINT or SIGTERM signal and forcibly exits
+// on the second one. Optionally, it calls a reload function on SIGHUP signal.
+//
+// It is intended to be used as the last runner in the [Chain] at the program
+// entrypoint so that the signal cancels only the main callback context and
+// other runners in the chain shut down gracefully after callback returns. For
+// example:
+//
+//	err := process.Chain(app, process.Signals()).Run(ctx, callback)
+//
+// Note that runners that precede SignalsRunner in the chain would otherwise
+// observe the canceled context in their Run method.
+type SignalsRunner struct {
+	ch        <-chan os.Signal
+	reload    func(ctx context.Context)
+	forceExit func(sig os.Signal)
+}
+
+// Signals returns a new [SignalsRunner] instance that forcibly exits with
+// status 128+n on the second signal n.
+func Signals() *SignalsRunner {
+	return &SignalsRunner{
+		forceExit: exitOnSignal,
+	}
+}
+
+// WithReload returns a copy of the runner that calls the given function on
+// SIGHUP signal. The function is called with the context passed to callback
+// and blocks signal handling until it returns.
+func (r *SignalsRunner) WithReload(f func(ctx context.Context)) *SignalsRunner {
+	rc := *r
+	rc.reload = f
+	return &rc
+}
+
+// WithForceExit returns a copy of the runner that calls the given function
+// instead of exiting on the second termination signal.
+func (r *SignalsRunner) WithForceExit(f func(sig os.Signal)) *SignalsRunner {
+	rc := *r
+	rc.forceExit = f
+	return &rc
+}
+
+// WithChannel returns a copy of the runner that receives signals from the given
+// channel instead of subscribing to OS signals. It is mostly useful for tests.
+// The runner stops handling signals if the channel is closed.
+func (r *SignalsRunner) WithChannel(ch <-chan os.Signal) *SignalsRunner {
+	rc := *r
+	rc.ch = ch
+	return &rc
+}
+
+// Run implements the [Runner] interface. It returns the callback error and
+// stops handling signals after callback returns.
+func (r *SignalsRunner) Run(ctx context.Context, callback Callback) error {
+	ch := r.ch
+	if ch == nil {
+		signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+		if r.reload != nil {
+			signals = append(signals, syscall.SIGHUP)
+		}
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, signals...)
+		defer signal.Stop(c)
+		ch = c
+	}
+
+	fgctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		r.handle(fgctx, ch, done, cancel)
+	}()
+
+	err := callback(fgctx)
+	close(done)
+	<-stopped
+	return err
+}
+
+// handle handles signals from ch until done or ch is closed.
+func (r *SignalsRunner) handle(ctx context.Context, ch <-chan os.Signal, done <-chan struct{}, cancel context.CancelCauseFunc) {
+	var terminating bool
+	for {
+		var sig os.Signal
+		var ok bool
+		select {
+		case <-done:
+			return
+		case sig, ok = <-ch:
+		}
+		if !ok {
+			return
+		}
+
+		if sig == syscall.SIGHUP {
+			if r.reload != nil {
+				r.reload(ctx)
+			}
+			continue
+		}
+
+		if terminating {
+			if r.forceExit != nil {
+				r.forceExit(sig)
+			}
+			continue
+		}
+		terminating = true
+		cancel(&SignalError{sig})
+	}
+}
+
+// exitOnSignal exits the program with status 128+n for signal n.
+func exitOnSignal(sig os.Signal) {
+	code := 1
+	if s, ok := sig.(syscall.Signal); ok {
+		code = 128 + int(s)
+	}
+	os.Exit(code)
+}
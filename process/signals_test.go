@@ -0,0 +1,98 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSignals(t *testing.T) {
+	ch := make(chan os.Signal)
+	reloaded := make(chan struct{})
+	exited := make(chan os.Signal)
+
+	r := Signals().
+		WithChannel(ch).
+		WithReload(func(_ context.Context) {
+			reloaded <- struct{}{}
+		}).
+		WithForceExit(func(sig os.Signal) {
+			exited <- sig
+		})
+
+	err := r.Run(context.Background(), func(ctx context.Context) error {
+		ch <- syscall.SIGHUP
+		<-reloaded
+		if ctx.Err() != nil {
+			t.Fatal("unexpected context cancellation on reload")
+		}
+
+		ch <- syscall.SIGTERM
+		<-ctx.Done()
+
+		var signalError *SignalError
+		if !errors.As(context.Cause(ctx), &signalError) || signalError.Signal != syscall.SIGTERM {
+			t.Fatalf("expected %v signal error, got %v", syscall.SIGTERM, context.Cause(ctx))
+		}
+
+		ch <- os.Interrupt
+		if sig := <-exited; sig != os.Interrupt {
+			t.Fatalf("expected %v, got %v", os.Interrupt, sig)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSignalsChain(t *testing.T) {
+	ch := make(chan os.Signal)
+
+	var stopError error
+	app := Leaf(
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		func(ctx context.Context) error {
+			stopError = ctx.Err()
+			return nil
+		},
+	)
+
+	err := Chain(app, Signals().WithChannel(ch)).Run(context.Background(), func(ctx context.Context) error {
+		ch <- syscall.SIGTERM
+		<-ctx.Done()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stopError != nil {
+		t.Fatalf("expected live context on graceful stop, got %v", stopError)
+	}
+}
+
+func TestSignalsClosedChannel(t *testing.T) {
+	ch := make(chan os.Signal)
+	close(ch)
+
+	r := Signals().
+		WithChannel(ch).
+		WithForceExit(func(sig os.Signal) {
+			t.Errorf("unexpected forced exit on %v", sig)
+		})
+
+	err := r.Run(context.Background(), func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Fatal("unexpected context cancellation on closed channel")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
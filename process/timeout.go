@@ -0,0 +1,147 @@
+package process
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Phase is a phase of the process lifecycle.
+type Phase int
+
+const (
+	// PhaseStartup is the phase between Run invocation and callback call.
+	PhaseStartup Phase = iota
+	// PhaseShutdown is the phase between callback return and Run return.
+	PhaseShutdown
+)
+
+// String implements the fmt.Stringer interface.
+func (p Phase) String() string {
+	switch p {
+	case PhaseStartup:
+		return "startup"
+	case PhaseShutdown:
+		return "shutdown"
+	default:
+		return "Phase(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// TimeoutError is an error that is returned from runners created with
+// [Timeout] if the phase timeout is exceeded.
+type TimeoutError struct {
+	// Name is the name of the runner.
+	Name string
+	// Phase is the phase that timed out.
+	Phase Phase
+	// Timeout is the exceeded timeout.
+	Timeout time.Duration
+	// Err is the error returned from the runner after forced shutdown.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	msg := "process: " + e.Name + ": " + e.Phase.String() + " timed out after " + e.Timeout.String()
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout returns a [Runner] instance that limits the duration of startup and
+// shutdown phases of the given named runner. A non-positive timeout disables
+// the limit for the corresponding phase.
+//
+// If a timeout is exceeded, the context passed to the runner is canceled with
+// a [TimeoutError] cause, signaling a forced shutdown. Run returns callback
+// error if it is not nil, otherwise it returns a [TimeoutError] that names the
+// runner and wraps the error returned from the runner. Note that the runner
+// must respect context cancellation for timeouts to be effective.
+func Timeout(name string, runner Runner, startup, shutdown time.Duration) Runner {
+	return &timeoutRunner{
+		name:     name,
+		runner:   runner,
+		startup:  startup,
+		shutdown: shutdown,
+	}
+}
+
+type timeoutRunner struct {
+	name     string
+	runner   Runner
+	startup  time.Duration
+	shutdown time.Duration
+}
+
+func (r *timeoutRunner) children() []childRunner {
+	return []childRunner{{r.name, r.runner}}
+}
+
+func (r *timeoutRunner) Run(ctx context.Context, callback Callback) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var shutdownError *TimeoutError
+	startupError, stop := r.arm(PhaseStartup, r.startup, cancel)
+
+	var callbackError error
+	err := runChild(ctx, r, 0, r.runner, func(ctx context.Context) error {
+		stop()
+		if isTimeoutCause(ctx, startupError) {
+			// Startup timed out but the runner has not observed
+			// context cancellation yet.
+			return nil
+		}
+		callbackError = callback(ctx)
+		shutdownError, stop = r.arm(PhaseShutdown, r.shutdown, cancel)
+		return callbackError
+	})
+	stop()
+
+	var timeoutError *TimeoutError
+	switch {
+	case isTimeoutCause(ctx, startupError):
+		timeoutError = startupError
+	case isTimeoutCause(ctx, shutdownError):
+		timeoutError = shutdownError
+	}
+
+	switch {
+	case callbackError != nil:
+		return callbackError
+	case timeoutError != nil:
+		e := *timeoutError
+		e.Err = err
+		return &e
+	}
+	return err
+}
+
+// arm starts a timer that cancels the context with a TimeoutError for the given
+// phase. It returns the error and a function that stops the timer.
+func (r *timeoutRunner) arm(phase Phase, d time.Duration, cancel context.CancelCauseFunc) (*TimeoutError, func()) {
+	if d <= 0 {
+		return nil, func() {}
+	}
+	err := &TimeoutError{
+		Name:    r.name,
+		Phase:   phase,
+		Timeout: d,
+	}
+	t := time.AfterFunc(d, func() {
+		cancel(err)
+	})
+	return err, func() { t.Stop() }
+}
+
+// isTimeoutCause reports whether the context was canceled with the given error.
+func isTimeoutCause(ctx context.Context, err *TimeoutError) bool {
+	return err != nil && context.Cause(ctx) == error(err)
+}
@@ -0,0 +1,70 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeoutStartup(t *testing.T) {
+	runner := Timeout("db", RunnerFunc(func(ctx context.Context, _ Callback) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), time.Millisecond, 0)
+
+	err := runner.Run(context.Background(), func(_ context.Context) error {
+		t.Fatal("unexpected callback")
+		return nil
+	})
+
+	var timeoutError *TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if timeoutError.Name != "db" || timeoutError.Phase != PhaseStartup {
+		t.Fatalf("unexpected timeout error: %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestTimeoutShutdown(t *testing.T) {
+	runner := Timeout("http", Leaf(
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	), time.Minute, time.Millisecond)
+
+	err := runner.Run(context.Background(), func(_ context.Context) error {
+		return nil
+	})
+
+	var timeoutError *TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if timeoutError.Name != "http" || timeoutError.Phase != PhaseShutdown {
+		t.Fatalf("unexpected timeout error: %v", err)
+	}
+	expected := "process: http: shutdown timed out after 1ms: context canceled"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTimeoutNotExceeded(t *testing.T) {
+	runner := Timeout("nop", Nop(), time.Minute, time.Minute)
+	err := runner.Run(context.Background(), func(_ context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}